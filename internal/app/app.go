@@ -11,6 +11,7 @@ import (
 	"golang.org/x/oauth2"
 
 	"github.com/gphotosuploader/gphotos-uploader-cli/internal/config"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/datastore/albumcounter"
 	"github.com/gphotosuploader/gphotos-uploader-cli/internal/datastore/tokenmanager"
 	"github.com/gphotosuploader/gphotos-uploader-cli/internal/datastore/upload_tracker"
 	"github.com/gphotosuploader/gphotos-uploader-cli/internal/log"
@@ -29,6 +30,8 @@ type App struct {
 	TokenManager TokenManager
 	// UploadSessionTracker tracks uploads sessions to implement resumable uploads.
 	UploadSessionTracker UploadSessionTracker
+	// AlbumCounter keeps the number of media items of the albums.
+	AlbumCounter AlbumCounter
 
 	// Client is the HTTP client after authentication.
 	Client *http.Client
@@ -88,6 +91,33 @@ func StartServices(ctx context.Context, path string) (*App, error) {
 	return app, nil
 }
 
+// StartOffline initializes the application with the services that don't need authentication nor network access:
+// the configuration and the file tracker.
+// The provided path is the expanded and absolute path to the application data folder.
+func StartOffline(path string) (*App, error) {
+	var err error
+
+	app := &App{
+		appDir: path,
+		Logger: log.GetInstance(),
+		fs:     afero.NewOsFs(),
+	}
+
+	app.Logger.Infof("Reading configuration from '%s'", app.configFilename())
+	app.Config, err = config.FromFile(app.fs, app.configFilename(), app.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration at '%s': %s", app.configFilename(), err)
+	}
+
+	app.FileTracker, err = app.defaultFileTracker()
+	if err != nil {
+		app.Logger.Errorf("File tracker could not be started, err: %s", err)
+		return nil, fmt.Errorf("file tracker could not be started, err: %s", err)
+	}
+
+	return app, nil
+}
+
 // StartWithoutConfig initializes the application without reading the configuration.
 // The provided path is the expanded and absolute path to the application data folder.
 func StartWithoutConfig(fs afero.Fs, path string) (*App, error) {
@@ -103,21 +133,36 @@ func StartWithoutConfig(fs afero.Fs, path string) (*App, error) {
 }
 
 // Stop stops the application releasing all service resources.
+// Services that have not been started are skipped.
 func (app *App) Stop() error {
 	// Close already uploaded file tracker
-	app.Logger.Debug("Shutting down File Tracker service...")
-	if err := app.FileTracker.Close(); err != nil {
-		return err
+	if app.FileTracker != nil {
+		app.Logger.Debug("Shutting down File Tracker service...")
+		if err := app.FileTracker.Close(); err != nil {
+			return err
+		}
 	}
 
 	// Close upload session tracker
-	app.Logger.Debug("Shutting down Upload Tracker service...")
-	app.UploadSessionTracker.Close()
+	if app.UploadSessionTracker != nil {
+		app.Logger.Debug("Shutting down Upload Tracker service...")
+		app.UploadSessionTracker.Close()
+	}
+
+	// Close album counter
+	if app.AlbumCounter != nil {
+		app.Logger.Debug("Shutting down Album Counter service...")
+		if err := app.AlbumCounter.Close(); err != nil {
+			return err
+		}
+	}
 
 	// Close token manager
-	app.Logger.Debug("Shutting down Token Manager service...")
-	if err := app.TokenManager.Close(); err != nil {
-		return err
+	if app.TokenManager != nil {
+		app.Logger.Debug("Shutting down Token Manager service...")
+		if err := app.TokenManager.Close(); err != nil {
+			return err
+		}
 	}
 
 	app.Logger.Debug("All services have been shut down successfully")
@@ -168,6 +213,11 @@ func (app *App) startServices() error {
 		app.Logger.Errorf("Uploads session tracker could not be started, err: %s", err)
 		return fmt.Errorf("uploads session tracker could not be started, err:%s", err)
 	}
+	app.AlbumCounter, err = app.defaultAlbumCounter()
+	if err != nil {
+		app.Logger.Errorf("Album counter could not be started, err: %s", err)
+		return fmt.Errorf("album counter could not be started, err:%s", err)
+	}
 	return nil
 }
 
@@ -194,6 +244,11 @@ func (app *App) defaultUploadsSessionTracker() (*upload_tracker.LevelDBStore, er
 	return upload_tracker.NewStore(ongoingUploadsTrackerFolder)
 }
 
+func (app *App) defaultAlbumCounter() (*albumcounter.LevelDBStore, error) {
+	albumCountsFolder := filepath.Join(app.appDir, "album_counts")
+	return albumcounter.NewStore(albumCountsFolder)
+}
+
 func (app *App) emptyDir(path string) error {
 	if err := app.fs.RemoveAll(path); err != nil {
 		return err
@@ -204,8 +259,13 @@ func (app *App) emptyDir(path string) error {
 // FileTracker represents a service to track file already uploaded.
 type FileTracker interface {
 	MarkAsUploaded(file string) error
+	MarkAsPaired(file string, sibling string) error
+	MarkAsPendingAlbums(file string, mediaItemID string) error
 	IsUploaded(file string) bool
+	PendingMediaItem(file string) (string, bool)
 	UnmarkAsUploaded(file string) error
+	TrackedFile(file string) (filetracker.TrackedFile, bool)
+	Hash(file string) (string, error)
 	Close() error
 
 	Destroy() error
@@ -227,3 +287,10 @@ type UploadSessionTracker interface {
 	Delete(fingerprint string)
 	Close()
 }
+
+// AlbumCounter represents a service to keep the number of media items of the albums.
+type AlbumCounter interface {
+	Get(albumID string) (int64, bool)
+	Set(albumID string, n int64) error
+	Close() error
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"time"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/config"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/filter"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/upload"
+)
+
+// NewUploadFolderJob returns the upload job for the given job configuration.
+// Files are tracked using the application FileTracker.
+func (app *App) NewUploadFolderJob(job config.FolderUploadJob) (*upload.UploadFolderJob, error) {
+	filterOptions := filter.Options{CaseInsensitive: job.CaseInsensitivePatterns, Tags: app.Config.PatternTags}
+	filterFiles, err := newFilter(job, filterOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	filenameDateParser, err := upload.NewFilenameDateParser(job.FilenameDatePatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	albumRules, err := newAlbumRules(job.AlbumRules, filterOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions, err := newFileConditions(job.FileConditions)
+	if err != nil {
+		return nil, err
+	}
+
+	var eventGap time.Duration
+	if job.EventGap != "" {
+		if eventGap, err = time.ParseDuration(job.EventGap); err != nil {
+			return nil, err
+		}
+	}
+
+	return &upload.UploadFolderJob{
+		FileTracker: app.FileTracker,
+
+		SourceFolder:    job.SourceFolder,
+		Album:           job.Album,
+		AlbumRules:      albumRules,
+		Albums:          job.Albums,
+		AlbumNameLocale: job.AlbumNameLocale,
+		AlbumMap:        job.AlbumMap,
+		AlbumTitles:     newAlbumTitleOptions(job.AlbumTitles),
+		Filter:          filterFiles,
+		ItemsOrder:      job.AlbumItemsOrder,
+		DateSources:     job.DateSources,
+
+		DetectByContent:         job.DetectByContent,
+		Conditions:              conditions,
+		CaseInsensitivePatterns: job.CaseInsensitivePatterns,
+		RawJpegPairing:          job.RawJpegPairing,
+		LivePhotoPairing:        job.LivePhotoPairing,
+
+		FilenameDateParser: filenameDateParser,
+		EventGap:           eventGap,
+		EventAlbumName:     job.EventAlbumName,
+	}, nil
+}
+
+// newFilter returns the file filter of the job, using the FilterRules option if it's set, or the IncludePatterns and
+// ExcludePatterns options otherwise.
+func newFilter(job config.FolderUploadJob, options filter.Options) (*filter.Filter, error) {
+	if len(job.FilterRules) > 0 {
+		return filter.CompileRules(job.FilterRules, options)
+	}
+	return filter.CompileWithOptions(job.IncludePatterns, job.ExcludePatterns, options)
+}
+
+// newFileConditions returns the upload file conditions for the given configuration. Capture dates are local days,
+// so the last day is included until its end.
+func newFileConditions(conditions *config.FileConditions) (upload.FileConditions, error) {
+	var result upload.FileConditions
+	if conditions == nil {
+		return result, nil
+	}
+
+	var err error
+	if conditions.MinSize != "" {
+		if result.MinSize, err = upload.ParseFileSize(conditions.MinSize); err != nil {
+			return result, err
+		}
+	}
+	if conditions.MaxSize != "" {
+		if result.MaxSize, err = upload.ParseFileSize(conditions.MaxSize); err != nil {
+			return result, err
+		}
+	}
+	if conditions.NewerThan != "" {
+		if result.NewerThan, err = upload.ParseAge(conditions.NewerThan); err != nil {
+			return result, err
+		}
+	}
+	if conditions.OlderThan != "" {
+		if result.OlderThan, err = upload.ParseAge(conditions.OlderThan); err != nil {
+			return result, err
+		}
+	}
+	if conditions.CapturedFrom != "" {
+		if result.CapturedFrom, err = time.ParseInLocation(time.DateOnly, conditions.CapturedFrom, time.Local); err != nil {
+			return result, err
+		}
+	}
+	if conditions.CapturedTo != "" {
+		capturedTo, err := time.ParseInLocation(time.DateOnly, conditions.CapturedTo, time.Local)
+		if err != nil {
+			return result, err
+		}
+		result.CapturedBefore = capturedTo.AddDate(0, 0, 1)
+	}
+	return result, nil
+}
+
+// newAlbumTitleOptions returns the upload album title options for the given configuration.
+func newAlbumTitleOptions(titles *config.AlbumTitles) upload.AlbumTitleOptions {
+	if titles == nil {
+		return upload.AlbumTitleOptions{}
+	}
+	return upload.AlbumTitleOptions{
+		Normalize:    titles.Normalize,
+		MaxLength:    titles.MaxLength,
+		Replacements: titles.Replacements,
+		DefaultAlbum: titles.DefaultAlbum,
+	}
+}
+
+// newAlbumRules returns the upload album rules for the given configuration.
+func newAlbumRules(rules []config.AlbumRule, filterOptions filter.Options) ([]upload.AlbumRule, error) {
+	result := make([]upload.AlbumRule, 0, len(rules))
+	for _, rule := range rules {
+		r := upload.AlbumRule{
+			Extensions:  rule.Extensions,
+			CameraModel: rule.CameraModel,
+			Album:       rule.Album,
+		}
+
+		if rule.Pattern != "" {
+			f, err := filter.CompileWithOptions([]string{rule.Pattern}, nil, filterOptions)
+			if err != nil {
+				return nil, err
+			}
+			r.Filter = f
+		}
+
+		var err error
+		if rule.MinSize != "" {
+			if r.MinSize, err = upload.ParseFileSize(rule.MinSize); err != nil {
+				return nil, err
+			}
+		}
+		if rule.MaxSize != "" {
+			if r.MaxSize, err = upload.ParseFileSize(rule.MaxSize); err != nil {
+				return nil, err
+			}
+		}
+
+		result = append(result, r)
+	}
+	return result, nil
+}
@@ -0,0 +1,94 @@
+package album
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/albums"
+	"github.com/spf13/cobra"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/cli/flags"
+)
+
+func NewCommand(globalFlags *flags.GlobalFlags) *cobra.Command {
+	albumCommand := &cobra.Command{
+		Use:   "album",
+		Short: "Manage albums created by this CLI in Google Photos",
+	}
+
+	albumCommand.AddCommand(initCreateCommand(globalFlags))
+	albumCommand.AddCommand(initRenameCommand(globalFlags))
+	albumCommand.AddCommand(initAddCommand(globalFlags))
+	albumCommand.AddCommand(initRemoveCommand(globalFlags))
+	albumCommand.AddCommand(initCoverCommand(globalFlags))
+	albumCommand.AddCommand(initPreviewCommand(globalFlags))
+
+	return albumCommand
+}
+
+const (
+	tableFormat = "table"
+	jsonFormat  = "json"
+)
+
+// outputOptions contains the flags to format the output of the album commands.
+type outputOptions struct {
+	Format    string
+	NoHeaders bool
+}
+
+func (o *outputOptions) addFlags(command *cobra.Command) {
+	command.Flags().StringVar(&o.Format, "format", tableFormat, "Output format. Valid formats are: table, json.")
+	command.Flags().BoolVar(&o.NoHeaders, "no-headers", false, "Don't print the header (only for table format).")
+}
+
+func (o *outputOptions) validate() error {
+	switch o.Format {
+	case tableFormat, jsonFormat:
+		return nil
+	}
+	return fmt.Errorf("invalid output format '%s', valid formats are: table, json", o.Format)
+}
+
+// albumOutput is the representation of an album in JSON format.
+type albumOutput struct {
+	ID              string `json:"id"`
+	Title           string `json:"title"`
+	TotalMediaItems int64  `json:"totalMediaItems"`
+	ProductURL      string `json:"productUrl"`
+	IsWriteable     bool   `json:"isWriteable"`
+}
+
+func (o *outputOptions) printAlbum(album *albums.Album, writer io.Writer) error {
+	if o.Format == jsonFormat {
+		return o.printAsJSON(album, writer)
+	}
+	o.printAsTable(album, writer)
+	return nil
+}
+
+func (o *outputOptions) printAsJSON(album *albums.Album, writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(albumOutput{
+		ID:              album.ID,
+		Title:           album.Title,
+		TotalMediaItems: album.TotalMediaItems,
+		ProductURL:      album.ProductURL,
+		IsWriteable:     album.IsWriteable,
+	})
+}
+
+func (o *outputOptions) printAsTable(album *albums.Album, writer io.Writer) {
+	w := tabwriter.NewWriter(writer, 0, 0, 1, ' ', 0)
+
+	if !o.NoHeaders {
+		fmt.Fprintln(w, "TITLE\t ITEMS\t ID\t") //nolint:errcheck
+	}
+
+	fmt.Fprintf(w, "%s\t %d\t %s\t\n", album.Title, album.TotalMediaItems, album.ID) //nolint:errcheck
+
+	w.Flush() //nolint:errcheck
+}
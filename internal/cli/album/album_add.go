@@ -0,0 +1,81 @@
+package album
+
+import (
+	"context"
+
+	gphotos "github.com/gphotosuploader/google-photos-api-client-go/v3"
+	"github.com/spf13/cobra"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/app"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/cli/flags"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/photos"
+)
+
+// AddCommandOptions contains the input to the 'album add' command.
+type AddCommandOptions struct {
+	*flags.GlobalFlags
+	outputOptions
+}
+
+func initAddCommand(globalFlags *flags.GlobalFlags) *cobra.Command {
+	o := &AddCommandOptions{
+		GlobalFlags: globalFlags,
+	}
+
+	command := &cobra.Command{
+		Use:   "add <album-id> <media-item-id>...",
+		Short: "Add media items to an album",
+		Long:  `Add one or more media items to an album created by this CLI. Only media items uploaded by this CLI can be added.`,
+		Args:  cobra.MinimumNArgs(2),
+		RunE:  o.Run,
+	}
+
+	o.addFlags(command)
+
+	return command
+}
+
+func (o *AddCommandOptions) Run(cobraCmd *cobra.Command, args []string) error {
+	if err := o.validate(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	cli, err := app.Start(ctx, o.CfgDir)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = cli.Stop()
+	}()
+
+	client, err := gphotos.NewClient(cli.Client)
+	if err != nil {
+		return err
+	}
+
+	albumID, mediaItemIDs := args[0], args[1:]
+
+	for _, batch := range batches(mediaItemIDs) {
+		cli.Logger.Debugf("Adding %d media items to album '%s'...", len(batch), albumID)
+		if err := client.Albums.AddMediaItems(ctx, albumID, batch); err != nil {
+			return err
+		}
+	}
+
+	album, err := client.Albums.GetById(ctx, albumID)
+	if err != nil {
+		return err
+	}
+
+	return o.printAlbum(album, cobraCmd.OutOrStdout())
+}
+
+// batches splits the media items in batches of the maximum size allowed by the API.
+func batches(mediaItemIDs []string) [][]string {
+	var result [][]string
+	for start := 0; start < len(mediaItemIDs); start += photos.MaxMediaItemsPerBatch {
+		result = append(result, mediaItemIDs[start:min(start+photos.MaxMediaItemsPerBatch, len(mediaItemIDs))])
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+package album
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/app"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/cli/flags"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/photos"
+)
+
+// CoverCommandOptions contains the input to the 'album cover' command.
+type CoverCommandOptions struct {
+	*flags.GlobalFlags
+	outputOptions
+}
+
+func initCoverCommand(globalFlags *flags.GlobalFlags) *cobra.Command {
+	o := &CoverCommandOptions{
+		GlobalFlags: globalFlags,
+	}
+
+	command := &cobra.Command{
+		Use:   "cover <album-id> <media-item-id>",
+		Short: "Set the cover photo of an album",
+		Long:  `Set the cover photo of an album created by this CLI. The media item must be in the album.`,
+		Args:  cobra.ExactArgs(2),
+		RunE:  o.Run,
+	}
+
+	o.addFlags(command)
+
+	return command
+}
+
+func (o *CoverCommandOptions) Run(cobraCmd *cobra.Command, args []string) error {
+	if err := o.validate(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	cli, err := app.Start(ctx, o.CfgDir)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = cli.Stop()
+	}()
+
+	cli.Logger.Debugf("Setting media item '%s' as cover photo of album '%s'...", args[1], args[0])
+
+	album, err := photos.NewAlbumsService(cli.Client).SetCoverPhoto(ctx, args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	return o.printAlbum(album, cobraCmd.OutOrStdout())
+}
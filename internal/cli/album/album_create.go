@@ -0,0 +1,64 @@
+package album
+
+import (
+	"context"
+
+	gphotos "github.com/gphotosuploader/google-photos-api-client-go/v3"
+	"github.com/spf13/cobra"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/app"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/cli/flags"
+)
+
+// CreateCommandOptions contains the input to the 'album create' command.
+type CreateCommandOptions struct {
+	*flags.GlobalFlags
+	outputOptions
+}
+
+func initCreateCommand(globalFlags *flags.GlobalFlags) *cobra.Command {
+	o := &CreateCommandOptions{
+		GlobalFlags: globalFlags,
+	}
+
+	command := &cobra.Command{
+		Use:   "create <title>",
+		Short: "Create an album",
+		Long:  `Create a new album in Google Photos with the given title.`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  o.Run,
+	}
+
+	o.addFlags(command)
+
+	return command
+}
+
+func (o *CreateCommandOptions) Run(cobraCmd *cobra.Command, args []string) error {
+	if err := o.validate(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	cli, err := app.Start(ctx, o.CfgDir)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = cli.Stop()
+	}()
+
+	client, err := gphotos.NewClient(cli.Client)
+	if err != nil {
+		return err
+	}
+
+	cli.Logger.Debugf("Creating album '%s'...", args[0])
+
+	album, err := client.Albums.Create(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	return o.printAlbum(album, cobraCmd.OutOrStdout())
+}
@@ -0,0 +1,292 @@
+package album
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/app"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/cli/flags"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/config"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/upload"
+)
+
+const (
+	// defaultPreviewSamples is the default number of sample files shown for every album.
+	defaultPreviewSamples = 3
+
+	// noAlbumTitle is the title used for files uploaded without an album.
+	noAlbumTitle = "(no album)"
+)
+
+// PreviewCommandOptions contains the input to the 'album preview' command.
+type PreviewCommandOptions struct {
+	*flags.GlobalFlags
+	outputOptions
+
+	// Template is an album name template used instead of the configured Album option.
+	Template string
+	// Samples is the number of sample files shown for every album.
+	Samples int
+	// All includes the files that have already been uploaded.
+	All bool
+}
+
+func initPreviewCommand(globalFlags *flags.GlobalFlags) *cobra.Command {
+	o := &PreviewCommandOptions{
+		GlobalFlags: globalFlags,
+	}
+
+	command := &cobra.Command{
+		Use:   "preview [source-folder]",
+		Short: "Preview the albums of the configured jobs",
+		Long: `Scan the source folders of the configured jobs and show the albums their files would be uploaded to.
+It works offline: no authentication is needed and nothing is uploaded.
+
+Use --template to compare the configured albums with the ones calculated by another album name template.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: o.Run,
+	}
+
+	o.addFlags(command)
+	command.Flags().StringVar(&o.Template, "template", "", "Album name template to compare with the configured Album option, like '%_year% - %_directory%'.")
+	command.Flags().IntVar(&o.Samples, "samples", defaultPreviewSamples, "Number of sample files shown for every album.")
+	command.Flags().BoolVar(&o.All, "all", false, "Include files that have already been uploaded.")
+
+	return command
+}
+
+func (o *PreviewCommandOptions) Run(cobraCmd *cobra.Command, args []string) error {
+	if err := o.validate(); err != nil {
+		return err
+	}
+
+	o.Template = strings.TrimPrefix(o.Template, "template:")
+	if o.Template != "" {
+		if err := upload.ValidateAlbumNameTemplate(o.Template); err != nil {
+			return fmt.Errorf("invalid template '%s': %w", o.Template, err)
+		}
+	}
+
+	cli, err := app.StartOffline(o.CfgDir)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = cli.Stop()
+	}()
+
+	jobs, err := selectJobs(cli.Config.Jobs, args)
+	if err != nil {
+		return err
+	}
+
+	var previews []jobPreview
+	for _, job := range jobs {
+		folder, err := cli.NewUploadFolderJob(job)
+		if err != nil {
+			return err
+		}
+		if o.All {
+			folder.FileTracker = noFileTracker{}
+		}
+
+		preview, err := o.previewJob(cli, folder, "")
+		if err != nil {
+			return err
+		}
+		previews = append(previews, preview)
+
+		if o.Template == "" {
+			continue
+		}
+
+		// Only the album name is compared, so the rest of album options are ignored.
+		folder.Album = "template:" + o.Template
+		folder.Albums = nil
+		folder.AlbumMap = nil
+
+		preview, err = o.previewJob(cli, folder, o.Template)
+		if err != nil {
+			return err
+		}
+		previews = append(previews, preview)
+	}
+
+	return o.printPreviews(previews, cobraCmd.OutOrStdout())
+}
+
+func (o *PreviewCommandOptions) previewJob(cli *app.App, folder *upload.UploadFolderJob, template string) (jobPreview, error) {
+	items, err := folder.ScanFolder(cli.Logger)
+	if err != nil {
+		return jobPreview{}, fmt.Errorf("failed to process location '%s': %w", folder.SourceFolder, err)
+	}
+
+	return jobPreview{
+		SourceFolder: folder.SourceFolder,
+		Template:     template,
+		Albums:       previewAlbums(items, folder.SourceFolder, o.Samples),
+	}, nil
+}
+
+// selectJobs returns the jobs whose source folder is the given one, or all the jobs if no folder is given.
+func selectJobs(jobs []config.FolderUploadJob, args []string) ([]config.FolderUploadJob, error) {
+	if len(args) == 0 {
+		return jobs, nil
+	}
+
+	want := filepath.Clean(args[0])
+	for _, job := range jobs {
+		if filepath.Clean(job.SourceFolder) == want {
+			return []config.FolderUploadJob{job}, nil
+		}
+	}
+	return nil, fmt.Errorf("there is no job with source folder '%s'", args[0])
+}
+
+// noFileTracker is a FileTracker that considers all the files as not uploaded.
+type noFileTracker struct{}
+
+func (noFileTracker) MarkAsUploaded(string) error   { return nil }
+func (noFileTracker) IsUploaded(string) bool        { return false }
+func (noFileTracker) UnmarkAsUploaded(string) error { return nil }
+
+// jobPreview contains the albums calculated for the files of a job.
+type jobPreview struct {
+	SourceFolder string
+	// Template is the album name template used instead of the configured options, if any.
+	Template string
+	Albums   []albumPreview
+}
+
+// albumPreview contains the files that would be added to an album.
+type albumPreview struct {
+	Title string
+	Files int
+	// From and To are the capture times of the oldest and the newest files.
+	From    time.Time
+	To      time.Time
+	Samples []string
+}
+
+// previewAlbums groups the files by album, in order of appearance. Files are counted in all their albums.
+func previewAlbums(items []upload.FileItem, sourceFolder string, samples int) []albumPreview {
+	var previews []albumPreview
+	index := make(map[string]int)
+
+	add := func(title string, item upload.FileItem) {
+		i, found := index[title]
+		if !found {
+			i = len(previews)
+			index[title] = i
+			previews = append(previews, albumPreview{Title: title, From: item.CaptureTime, To: item.CaptureTime})
+		}
+
+		p := &previews[i]
+		p.Files++
+		if item.CaptureTime.Before(p.From) {
+			p.From = item.CaptureTime
+		}
+		if item.CaptureTime.After(p.To) {
+			p.To = item.CaptureTime
+		}
+		if len(p.Samples) < samples {
+			p.Samples = append(p.Samples, upload.RelativePath(sourceFolder, item.Path))
+		}
+	}
+
+	for _, item := range items {
+		add(albumTitle(item), item)
+		for _, name := range item.ExtraAlbumNames {
+			add(name, item)
+		}
+	}
+	return previews
+}
+
+// albumTitle returns the title of the album where the file is uploaded.
+func albumTitle(item upload.FileItem) string {
+	switch {
+	case item.AlbumID != "" && item.AlbumName != "":
+		return fmt.Sprintf("%s (id: %s)", item.AlbumName, item.AlbumID)
+	case item.AlbumID != "":
+		return fmt.Sprintf("(id: %s)", item.AlbumID)
+	case item.AlbumName != "":
+		return item.AlbumName
+	}
+	return noAlbumTitle
+}
+
+// albumPreviewOutput is the representation of an album preview in JSON format.
+type albumPreviewOutput struct {
+	Title   string    `json:"title"`
+	Files   int       `json:"files"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Samples []string  `json:"samples"`
+}
+
+// jobPreviewOutput is the representation of a job preview in JSON format.
+type jobPreviewOutput struct {
+	SourceFolder string               `json:"sourceFolder"`
+	Template     string               `json:"template,omitempty"`
+	Albums       []albumPreviewOutput `json:"albums"`
+}
+
+func (o *PreviewCommandOptions) printPreviews(previews []jobPreview, writer io.Writer) error {
+	if o.Format == jsonFormat {
+		return printPreviewsAsJSON(previews, writer)
+	}
+	for i, preview := range previews {
+		if i > 0 {
+			fmt.Fprintln(writer) //nolint:errcheck
+		}
+		o.printPreviewAsTable(preview, writer)
+	}
+	return nil
+}
+
+func printPreviewsAsJSON(previews []jobPreview, writer io.Writer) error {
+	output := make([]jobPreviewOutput, 0, len(previews))
+	for _, preview := range previews {
+		albums := make([]albumPreviewOutput, 0, len(preview.Albums))
+		for _, album := range preview.Albums {
+			albums = append(albums, albumPreviewOutput(album))
+		}
+		output = append(output, jobPreviewOutput{
+			SourceFolder: preview.SourceFolder,
+			Template:     preview.Template,
+			Albums:       albums,
+		})
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+func (o *PreviewCommandOptions) printPreviewAsTable(preview jobPreview, writer io.Writer) {
+	if preview.Template != "" {
+		fmt.Fprintf(writer, "Source folder '%s' using template '%s':\n", preview.SourceFolder, preview.Template) //nolint:errcheck
+	} else {
+		fmt.Fprintf(writer, "Source folder '%s':\n", preview.SourceFolder) //nolint:errcheck
+	}
+
+	w := tabwriter.NewWriter(writer, 0, 0, 1, ' ', 0)
+
+	if !o.NoHeaders {
+		fmt.Fprintln(w, "ALBUM\t FILES\t FROM\t TO\t SAMPLES\t") //nolint:errcheck
+	}
+
+	for _, album := range preview.Albums {
+		fmt.Fprintf(w, "%s\t %d\t %s\t %s\t %s\t\n", album.Title, album.Files, album.From.Format(time.DateOnly), album.To.Format(time.DateOnly), strings.Join(album.Samples, ", ")) //nolint:errcheck
+	}
+
+	w.Flush() //nolint:errcheck
+}
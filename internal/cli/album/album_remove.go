@@ -0,0 +1,73 @@
+package album
+
+import (
+	"context"
+
+	gphotos "github.com/gphotosuploader/google-photos-api-client-go/v3"
+	"github.com/spf13/cobra"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/app"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/cli/flags"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/photos"
+)
+
+// RemoveCommandOptions contains the input to the 'album remove' command.
+type RemoveCommandOptions struct {
+	*flags.GlobalFlags
+	outputOptions
+}
+
+func initRemoveCommand(globalFlags *flags.GlobalFlags) *cobra.Command {
+	o := &RemoveCommandOptions{
+		GlobalFlags: globalFlags,
+	}
+
+	command := &cobra.Command{
+		Use:   "remove <album-id> <media-item-id>...",
+		Short: "Remove media items from an album",
+		Long:  `Remove one or more media items from an album created by this CLI. Media items are not deleted from Google Photos.`,
+		Args:  cobra.MinimumNArgs(2),
+		RunE:  o.Run,
+	}
+
+	o.addFlags(command)
+
+	return command
+}
+
+func (o *RemoveCommandOptions) Run(cobraCmd *cobra.Command, args []string) error {
+	if err := o.validate(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	cli, err := app.Start(ctx, o.CfgDir)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = cli.Stop()
+	}()
+
+	client, err := gphotos.NewClient(cli.Client)
+	if err != nil {
+		return err
+	}
+
+	albumID, mediaItemIDs := args[0], args[1:]
+
+	service := photos.NewAlbumsService(cli.Client)
+	for _, batch := range batches(mediaItemIDs) {
+		cli.Logger.Debugf("Removing %d media items from album '%s'...", len(batch), albumID)
+		if err := service.RemoveMediaItems(ctx, albumID, batch); err != nil {
+			return err
+		}
+	}
+
+	album, err := client.Albums.GetById(ctx, albumID)
+	if err != nil {
+		return err
+	}
+
+	return o.printAlbum(album, cobraCmd.OutOrStdout())
+}
@@ -0,0 +1,59 @@
+package album
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/app"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/cli/flags"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/photos"
+)
+
+// RenameCommandOptions contains the input to the 'album rename' command.
+type RenameCommandOptions struct {
+	*flags.GlobalFlags
+	outputOptions
+}
+
+func initRenameCommand(globalFlags *flags.GlobalFlags) *cobra.Command {
+	o := &RenameCommandOptions{
+		GlobalFlags: globalFlags,
+	}
+
+	command := &cobra.Command{
+		Use:   "rename <album-id> <title>",
+		Short: "Rename an album",
+		Long:  `Change the title of an album created by this CLI.`,
+		Args:  cobra.ExactArgs(2),
+		RunE:  o.Run,
+	}
+
+	o.addFlags(command)
+
+	return command
+}
+
+func (o *RenameCommandOptions) Run(cobraCmd *cobra.Command, args []string) error {
+	if err := o.validate(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	cli, err := app.Start(ctx, o.CfgDir)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = cli.Stop()
+	}()
+
+	cli.Logger.Debugf("Renaming album '%s' to '%s'...", args[0], args[1])
+
+	album, err := photos.NewAlbumsService(cli.Client).UpdateTitle(ctx, args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	return o.printAlbum(album, cobraCmd.OutOrStdout())
+}
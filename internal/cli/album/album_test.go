@@ -0,0 +1,123 @@
+package album
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/albums"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/config"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/upload"
+)
+
+func TestOutputOptions_PrintAlbum(t *testing.T) {
+	album := &albums.Album{ID: "album-id", Title: "Summer Trip", TotalMediaItems: 3, ProductURL: "https://photos.google.com/album/album-id", IsWriteable: true}
+
+	testCases := []struct {
+		name    string
+		options outputOptions
+		want    string
+	}{
+		{name: "table", options: outputOptions{Format: tableFormat}, want: "TITLE        ITEMS  ID       \nSummer Trip  3      album-id \n"},
+		{name: "table without headers", options: outputOptions{Format: tableFormat, NoHeaders: true}, want: "Summer Trip  3  album-id \n"},
+		{name: "json", options: outputOptions{Format: jsonFormat}, want: `{
+  "id": "album-id",
+  "title": "Summer Trip",
+  "totalMediaItems": 3,
+  "productUrl": "https://photos.google.com/album/album-id",
+  "isWriteable": true
+}
+`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var b bytes.Buffer
+			assert.NoError(t, tc.options.printAlbum(album, &b))
+			assert.Equal(t, tc.want, b.String())
+		})
+	}
+}
+
+func TestOutputOptions_Validate(t *testing.T) {
+	assert.NoError(t, (&outputOptions{Format: tableFormat}).validate())
+	assert.NoError(t, (&outputOptions{Format: jsonFormat}).validate())
+	assert.Error(t, (&outputOptions{Format: "yaml"}).validate())
+}
+
+func TestBatches(t *testing.T) {
+	var ids []string
+	for i := 0; i < 120; i++ {
+		ids = append(ids, fmt.Sprintf("item-%d", i))
+	}
+
+	got := batches(ids)
+
+	assert.Len(t, got, 3)
+	assert.Len(t, got[0], 50)
+	assert.Len(t, got[1], 50)
+	assert.Len(t, got[2], 20)
+	assert.Equal(t, "item-119", got[2][19])
+}
+
+func TestPreviewAlbums(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, time.May, d, 10, 0, 0, 0, time.UTC) }
+	items := []upload.FileItem{
+		{Path: "/photos/trip/a.jpg", AlbumName: "Trip", ExtraAlbumNames: []string{"Family"}, CaptureTime: day(3)},
+		{Path: "/photos/trip/b.jpg", AlbumName: "Trip", CaptureTime: day(1)},
+		{Path: "/photos/trip/c.jpg", AlbumName: "Trip", CaptureTime: day(5)},
+		{Path: "/photos/d.jpg", CaptureTime: day(2)},
+		{Path: "/photos/work/e.jpg", AlbumName: "Work", AlbumID: "album-id", CaptureTime: day(4)},
+	}
+
+	got := previewAlbums(items, "/photos", 2)
+
+	assert.Equal(t, []albumPreview{
+		{Title: "Trip", Files: 3, From: day(1), To: day(5), Samples: []string{"trip/a.jpg", "trip/b.jpg"}},
+		{Title: "Family", Files: 1, From: day(3), To: day(3), Samples: []string{"trip/a.jpg"}},
+		{Title: "(no album)", Files: 1, From: day(2), To: day(2), Samples: []string{"d.jpg"}},
+		{Title: "Work (id: album-id)", Files: 1, From: day(4), To: day(4), Samples: []string{"work/e.jpg"}},
+	}, got)
+}
+
+func TestPreviewCommandOptions_PrintPreviews(t *testing.T) {
+	previews := []jobPreview{
+		{SourceFolder: "/photos", Albums: []albumPreview{
+			{Title: "Trip", Files: 2, From: time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2024, time.May, 3, 0, 0, 0, 0, time.UTC), Samples: []string{"a.jpg", "b.jpg"}},
+		}},
+		{SourceFolder: "/photos", Template: "%_year%", Albums: []albumPreview{
+			{Title: "2024", Files: 2, From: time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2024, time.May, 3, 0, 0, 0, 0, time.UTC), Samples: []string{"a.jpg", "b.jpg"}},
+		}},
+	}
+	o := &PreviewCommandOptions{outputOptions: outputOptions{Format: tableFormat}}
+
+	var b bytes.Buffer
+	assert.NoError(t, o.printPreviews(previews, &b))
+
+	assert.Equal(t, `Source folder '/photos':
+ALBUM  FILES  FROM        TO          SAMPLES      
+Trip   2      2024-05-01  2024-05-03  a.jpg, b.jpg 
+
+Source folder '/photos' using template '%_year%':
+ALBUM  FILES  FROM        TO          SAMPLES      
+2024   2      2024-05-01  2024-05-03  a.jpg, b.jpg 
+`, b.String())
+}
+
+func TestSelectJobs(t *testing.T) {
+	jobs := []config.FolderUploadJob{{SourceFolder: "/photos"}, {SourceFolder: "/videos/"}}
+
+	got, err := selectJobs(jobs, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, jobs, got)
+
+	got, err = selectJobs(jobs, []string{"/videos"})
+	assert.NoError(t, err)
+	assert.Equal(t, jobs[1:], got)
+
+	_, err = selectJobs(jobs, []string{"/music"})
+	assert.Error(t, err)
+}
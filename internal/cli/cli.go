@@ -2,7 +2,9 @@ package cli
 
 import (
 	"fmt"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/cli/album"
 	"github.com/gphotosuploader/gphotos-uploader-cli/internal/cli/auth"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/cli/filter"
 	"github.com/gphotosuploader/gphotos-uploader-cli/internal/cli/flags"
 	"github.com/gphotosuploader/gphotos-uploader-cli/internal/cli/list"
 	"github.com/gphotosuploader/gphotos-uploader-cli/internal/cli/push"
@@ -30,6 +32,9 @@ $ gphotos-uploder-cli push
 Or you can list your albums in Google Photos by running:
 $ gphotos-uploader-cli list albums
 
+Albums created by this CLI can be managed with the album commands, for example:
+$ gphotos-uploader-cli album rename <album-id> "New title"
+
 For more information, visit: https://gphotosuploader.github.io/gphotos-uploader-cli.
 `
 	globalFlags *flags.GlobalFlags
@@ -65,6 +70,8 @@ func createCliCommandTree(cmd *cobra.Command) {
 	cmd.AddCommand(push.NewCommand(globalFlags))
 	cmd.AddCommand(auth.NewCommand(globalFlags))
 	cmd.AddCommand(list.NewCommand(globalFlags))
+	cmd.AddCommand(album.NewCommand(globalFlags))
+	cmd.AddCommand(filter.NewCommand(globalFlags))
 	cmd.AddCommand(reset.NewCommand(globalFlags))
 
 	// TODO: Set flags here instead of passing globalFlags to all commands.
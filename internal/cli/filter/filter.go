@@ -0,0 +1,18 @@
+package filter
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/cli/flags"
+)
+
+func NewCommand(globalFlags *flags.GlobalFlags) *cobra.Command {
+	filterCommand := &cobra.Command{
+		Use:   "filter",
+		Short: "Inspect how the configured jobs select the files to upload",
+	}
+
+	filterCommand.AddCommand(initExplainCommand(globalFlags))
+
+	return filterCommand
+}
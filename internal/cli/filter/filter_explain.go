@@ -0,0 +1,203 @@
+package filter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/app"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/cli/flags"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/config"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/datastore/filetracker"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/upload"
+)
+
+// ExplainCommandOptions contains the input to the 'filter explain' command.
+type ExplainCommandOptions struct {
+	*flags.GlobalFlags
+
+	// Job is the source folder of the job used to explain the file. If it's empty, the job containing the file is
+	// used.
+	Job string
+}
+
+func initExplainCommand(globalFlags *flags.GlobalFlags) *cobra.Command {
+	o := &ExplainCommandOptions{
+		GlobalFlags: globalFlags,
+	}
+
+	command := &cobra.Command{
+		Use:   "explain <path>",
+		Short: "Explain why a file would be uploaded or skipped",
+		Long: `Show the checks done to a file by the next push: its excluded parent folders, the matching patterns
+and ignore file rules, the file conditions, its file tracker state, its pairing with other files and the album it
+would be uploaded to.
+It works offline: no authentication is needed and nothing is uploaded.`,
+		Args: cobra.ExactArgs(1),
+		RunE: o.Run,
+	}
+
+	command.Flags().StringVar(&o.Job, "job", "", "Source folder of the job used to explain the file. By default, the job containing the file.")
+
+	return command
+}
+
+func (o *ExplainCommandOptions) Run(cobraCmd *cobra.Command, args []string) error {
+	path, err := filepath.Abs(args[0])
+	if err != nil {
+		return err
+	}
+
+	cli, err := app.StartOffline(o.CfgDir)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = cli.Stop()
+	}()
+
+	job, err := selectJob(cli.Config.Jobs, path, o.Job)
+	if err != nil {
+		return err
+	}
+
+	folder, err := cli.NewUploadFolderJob(job)
+	if err != nil {
+		return err
+	}
+
+	tracked := trackerState{}
+	tracked.Stored, tracked.Found = cli.FileTracker.TrackedFile(path)
+	tracked.Hash, tracked.HashErr = cli.FileTracker.Hash(path)
+	if fi, err := os.Stat(path); err == nil {
+		tracked.ModTime = fi.ModTime()
+	}
+
+	explanation, err := folder.ExplainFile(path)
+	if err != nil {
+		return err
+	}
+
+	printExplanation(explanation, job.SourceFolder, tracked, cobraCmd.OutOrStdout())
+	return nil
+}
+
+// selectJob returns the job with the given source folder or, if it's empty, the job containing the file. If several
+// jobs contain the file, the one with the deepest source folder is used.
+func selectJob(jobs []config.FolderUploadJob, path string, sourceFolder string) (config.FolderUploadJob, error) {
+	if sourceFolder != "" {
+		want := filepath.Clean(sourceFolder)
+		for _, job := range jobs {
+			if filepath.Clean(job.SourceFolder) == want {
+				return job, nil
+			}
+		}
+		return config.FolderUploadJob{}, fmt.Errorf("there is no job with source folder '%s'", sourceFolder)
+	}
+
+	var selected config.FolderUploadJob
+	found := false
+	for _, job := range jobs {
+		if !isInFolder(path, job.SourceFolder) {
+			continue
+		}
+		if !found || len(job.SourceFolder) > len(selected.SourceFolder) {
+			selected, found = job, true
+		}
+	}
+	if !found {
+		return config.FolderUploadJob{}, fmt.Errorf("there is no job containing '%s'", path)
+	}
+	return selected, nil
+}
+
+// isInFolder returns true if the path is inside the folder.
+func isInFolder(path string, folder string) bool {
+	rel, err := filepath.Rel(filepath.Clean(folder), path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// trackerState is the file tracker state of a file, and its current modification time and hash.
+type trackerState struct {
+	Stored filetracker.TrackedFile
+	Found  bool
+
+	ModTime time.Time
+	Hash    string
+	HashErr error
+}
+
+func printExplanation(e upload.FileExplanation, sourceFolder string, tracked trackerState, writer io.Writer) {
+	fmt.Fprintf(writer, "File '%s' of source folder '%s':\n", e.RelativePath, sourceFolder) //nolint:errcheck
+
+	w := tabwriter.NewWriter(writer, 0, 0, 1, ' ', 0)
+
+	for _, step := range e.Steps {
+		result := "ok"
+		if step.Skips {
+			result = "skip"
+		}
+		fmt.Fprintf(w, "%s:\t %s\t %s\t\n", step.Name, result, step.Result) //nolint:errcheck
+	}
+
+	switch {
+	case tracked.Found && tracked.Stored.MediaItemID != "":
+		fmt.Fprintf(w, "Stored state:\t\t modified %s, hash %s, media item %s\t\n", formatModTime(tracked.Stored.ModTime), tracked.Stored.Hash, tracked.Stored.MediaItemID) //nolint:errcheck
+	case tracked.Found && tracked.Stored.PairedWith != "":
+		fmt.Fprintf(w, "Stored state:\t\t modified %s, hash %s, paired with '%s'\t\n", formatModTime(tracked.Stored.ModTime), tracked.Stored.Hash, tracked.Stored.PairedWith) //nolint:errcheck
+	case tracked.Found:
+		fmt.Fprintf(w, "Stored state:\t\t modified %s, hash %s\t\n", formatModTime(tracked.Stored.ModTime), tracked.Stored.Hash) //nolint:errcheck
+	default:
+		fmt.Fprintln(w, "Stored state:\t\t not tracked\t") //nolint:errcheck
+	}
+	if tracked.HashErr != nil {
+		fmt.Fprintf(w, "Current state:\t\t modified %s, hash error: %s\t\n", formatModTime(tracked.ModTime), tracked.HashErr) //nolint:errcheck
+	} else {
+		fmt.Fprintf(w, "Current state:\t\t modified %s, hash %s\t\n", formatModTime(tracked.ModTime), tracked.Hash) //nolint:errcheck
+	}
+
+	fmt.Fprintf(w, "Album:\t\t %s\t\n", albumTitle(e)) //nolint:errcheck
+
+	if step, skipped := e.SkippedBy(); skipped {
+		fmt.Fprintf(w, "Decision:\t\t skipped by %s\t\n", strings.ToLower(step.Name)) //nolint:errcheck
+	} else if e.PendingMediaItemID != "" {
+		fmt.Fprintln(w, "Decision:\t\t added to its extra albums by the next push, without uploading it again\t") //nolint:errcheck
+	} else {
+		fmt.Fprintln(w, "Decision:\t\t uploaded by the next push\t") //nolint:errcheck
+	}
+
+	w.Flush() //nolint:errcheck
+}
+
+// formatModTime returns the modification time, or "unknown" if it's not set.
+func formatModTime(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// albumTitle returns the albums where the file would be uploaded.
+func albumTitle(e upload.FileExplanation) string {
+	var title string
+	switch {
+	case e.AlbumID != "" && e.AlbumName != "":
+		title = fmt.Sprintf("%s (id: %s)", e.AlbumName, e.AlbumID)
+	case e.AlbumID != "":
+		title = fmt.Sprintf("(id: %s)", e.AlbumID)
+	case e.AlbumName != "":
+		title = e.AlbumName
+	default:
+		title = "(no album)"
+	}
+	if len(e.ExtraAlbumNames) > 0 {
+		title += ", " + strings.Join(e.ExtraAlbumNames, ", ")
+	}
+	return title
+}
@@ -0,0 +1,105 @@
+package filter
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/config"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/datastore/filetracker"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/upload"
+)
+
+func TestSelectJob(t *testing.T) {
+	jobs := []config.FolderUploadJob{{SourceFolder: "/photos"}, {SourceFolder: "/photos/trips/"}, {SourceFolder: "/videos"}}
+
+	testCases := []struct {
+		name          string
+		path          string
+		sourceFolder  string
+		want          string
+		isErrExpected bool
+	}{
+		{name: "Should select the job containing the file", path: "/videos/a.mp4", want: "/videos"},
+		{name: "Should select the deepest job", path: "/photos/trips/a.jpg", want: "/photos/trips/"},
+		{name: "Should select the given job", path: "/photos/trips/a.jpg", sourceFolder: "/photos", want: "/photos"},
+		{name: "Should fail if no job contains the file", path: "/photos-old/a.jpg", isErrExpected: true},
+		{name: "Should fail if the given job doesn't exist", path: "/photos/a.jpg", sourceFolder: "/music", isErrExpected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := selectJob(jobs, tc.path, tc.sourceFolder)
+			if tc.isErrExpected {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got.SourceFolder)
+		})
+	}
+}
+
+func TestPrintExplanation(t *testing.T) {
+	modTime := time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+	e := upload.FileExplanation{
+		RelativePath: "trip/a.jpg",
+		Steps: []upload.ExplanationStep{
+			{Name: "Patterns", Result: "included by pattern '_IMAGE_EXTENSIONS_'"},
+			{Name: "File tracker", Result: "already uploaded", Skips: true},
+		},
+		AlbumName:       "trip",
+		ExtraAlbumNames: []string{"Favorites"},
+	}
+	tracked := trackerState{
+		Stored:  filetracker.TrackedFile{ModTime: modTime, Hash: "1234"},
+		Found:   true,
+		ModTime: modTime,
+		Hash:    "1234",
+	}
+
+	var b bytes.Buffer
+	printExplanation(e, "/photos", tracked, &b)
+
+	assert.Equal(t, `File 'trip/a.jpg' of source folder '/photos':
+Patterns:       ok    included by pattern '_IMAGE_EXTENSIONS_' 
+File tracker:   skip  already uploaded                         
+Stored state:         modified 2024-05-01T10:00:00Z, hash 1234 
+Current state:        modified 2024-05-01T10:00:00Z, hash 1234 
+Album:                trip, Favorites                          
+Decision:             skipped by file tracker                  
+`, b.String())
+}
+
+func TestPrintExplanation_PendingExtraAlbums(t *testing.T) {
+	modTime := time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+	e := upload.FileExplanation{
+		RelativePath: "trip/a.jpg",
+		Steps: []upload.ExplanationStep{
+			{Name: "File tracker", Result: "uploaded, pending extra albums (media item item-1)"},
+		},
+		Uploaded:           true,
+		PendingMediaItemID: "item-1",
+		AlbumName:          "trip",
+		ExtraAlbumNames:    []string{"Favorites"},
+	}
+	tracked := trackerState{
+		Stored:  filetracker.TrackedFile{ModTime: modTime, Hash: "1234", MediaItemID: "item-1"},
+		Found:   true,
+		ModTime: modTime,
+		Hash:    "1234",
+	}
+
+	var b bytes.Buffer
+	printExplanation(e, "/photos", tracked, &b)
+
+	assert.Equal(t, `File 'trip/a.jpg' of source folder '/photos':
+File tracker:   ok  uploaded, pending extra albums (media item item-1)                     
+Stored state:       modified 2024-05-01T10:00:00Z, hash 1234, media item item-1            
+Current state:      modified 2024-05-01T10:00:00Z, hash 1234                               
+Album:              trip, Favorites                                                        
+Decision:           added to its extra albums by the next push, without uploading it again 
+`, b.String())
+}
@@ -0,0 +1,278 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	gphotos "github.com/gphotosuploader/google-photos-api-client-go/v3"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/config"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/log"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/photos"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/upload"
+)
+
+const (
+	// reuseFirstPolicy uploads to the first album found with the requested title.
+	reuseFirstPolicy = "reuse-first"
+	// errorPolicy refuses to upload to a title shared by several albums.
+	errorPolicy = "error"
+	// createSuffixedPolicy uses the first of "Title", "Title (2)", "Title (3)"... that is not shared by several albums.
+	createSuffixedPolicy = "create-suffixed"
+
+	// defaultAlbumSplitSuffix is the suffix added to the title of full albums to get the next album, like "Title (2)".
+	defaultAlbumSplitSuffix = " (%_part%)"
+	// albumSplitPartToken is replaced by the number of the album in the album split suffix.
+	albumSplitPartToken = "%_part%"
+)
+
+// albumCounter keeps the number of media items of the albums between executions.
+type albumCounter interface {
+	Get(albumID string) (int64, bool)
+	Set(albumID string, n int64) error
+}
+
+// albumResolver returns the album where files are uploaded, creating it if needed.
+// Album titles are not unique in Google Photos, so the policy sets what to do with duplicated titles.
+type albumResolver struct {
+	service gphotos.AlbumsService
+	// counts keeps the item counts of the albums between executions. It's optional.
+	counts albumCounter
+
+	// albumsByTitle keeps the IDs of the albums created by this app, indexed by title.
+	// It's loaded on first use.
+	albumsByTitle map[string][]string
+
+	// itemCounts keeps the number of media items of the albums, indexed by ID. It's loaded with the albums, and
+	// updated when media items are added to them.
+	// The Google Photos API can return an outdated number of items after adding them, so the biggest of the listed
+	// and the kept counts is used.
+	itemCounts map[string]int64
+
+	// plannedTitles are the titles of the albums that would be created in dry run mode.
+	plannedTitles map[string]bool
+}
+
+func newAlbumResolver(service gphotos.AlbumsService, counts albumCounter) *albumResolver {
+	return &albumResolver{
+		service: service,
+		counts:  counts,
+	}
+}
+
+// resolve returns the album ID for the given title using the specified duplicated titles policy, and
+// whether the album has been created. An empty title returns an empty album ID.
+func (r *albumResolver) resolve(ctx context.Context, title string, policy string) (string, bool, error) {
+	// Returns if empty to avoid a PhotosService call.
+	if title == "" {
+		return "", false, nil
+	}
+
+	if err := r.load(ctx); err != nil {
+		return "", false, err
+	}
+
+	switch policy {
+	case "", reuseFirstPolicy:
+		if ids := r.albumsByTitle[title]; len(ids) > 0 {
+			return ids[0], false, nil
+		}
+		return r.create(ctx, title)
+	case errorPolicy:
+		ids := r.albumsByTitle[title]
+		if len(ids) > 1 {
+			return "", false, fmt.Errorf("there are %d albums with the same title", len(ids))
+		}
+		if len(ids) == 1 {
+			return ids[0], false, nil
+		}
+		return r.create(ctx, title)
+	case createSuffixedPolicy:
+		for n := 1; ; n++ {
+			candidate := title
+			if n > 1 {
+				candidate = fmt.Sprintf("%s (%d)", title, n)
+			}
+			ids := r.albumsByTitle[candidate]
+			if len(ids) == 0 {
+				return r.create(ctx, candidate)
+			}
+			if len(ids) == 1 {
+				return ids[0], false, nil
+			}
+		}
+	}
+	return "", false, fmt.Errorf("unknown duplicated album policy '%s'", policy)
+}
+
+// dryRunResolve returns true the first time it's called for a title that no album has, like resolve would create
+// the album, but without creating it.
+func (r *albumResolver) dryRunResolve(ctx context.Context, title string) (bool, error) {
+	if err := r.load(ctx); err != nil {
+		return false, err
+	}
+	if len(r.albumsByTitle[title]) > 0 || r.plannedTitles[title] {
+		return false, nil
+	}
+	if r.plannedTitles == nil {
+		r.plannedTitles = make(map[string]bool)
+	}
+	r.plannedTitles[title] = true
+	return true, nil
+}
+
+// load gets all the albums created by this app, if they were not already loaded.
+func (r *albumResolver) load(ctx context.Context) error {
+	if r.albumsByTitle != nil {
+		return nil
+	}
+
+	albumsList, err := r.service.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.albumsByTitle = make(map[string][]string)
+	r.itemCounts = make(map[string]int64)
+	for _, album := range albumsList {
+		r.albumsByTitle[album.Title] = append(r.albumsByTitle[album.Title], album.ID)
+		r.itemCounts[album.ID] = album.TotalMediaItems
+		if r.counts == nil {
+			continue
+		}
+		if n, ok := r.counts.Get(album.ID); ok && n > album.TotalMediaItems {
+			r.itemCounts[album.ID] = n
+		}
+	}
+	return nil
+}
+
+// resolveWithRoom returns the album ID for the given title like resolve does, but skipping full albums.
+// Full albums are split: the next albums are named adding the split suffix to the title, like "Title (2)",
+// "Title (3)"... An empty suffix uses the default one. Titles are truncated to leave room for the suffix, so they
+// are not longer than maxLength.
+// The policy only applies to the title. The next albums reuse the first album found with their title, so the policy
+// doesn't add its own suffixes to them, like "Title (2) (2)".
+func (r *albumResolver) resolveWithRoom(ctx context.Context, title string, policy string, splitSuffix string, maxLength int) (string, bool, error) {
+	if splitSuffix == "" {
+		splitSuffix = defaultAlbumSplitSuffix
+	}
+
+	candidate := title
+	for part := 2; ; part++ {
+		albumID, created, err := r.resolve(ctx, candidate, policy)
+		if err != nil || albumID == "" || r.room(albumID) > 0 {
+			return albumID, created, err
+		}
+		candidate = upload.SplitAlbumTitle(title, strings.ReplaceAll(splitSuffix, albumSplitPartToken, strconv.Itoa(part)), maxLength)
+		policy = reuseFirstPolicy
+	}
+}
+
+// albumTitleMaxLength returns the maximum length of the album titles of the job. Zero means the default length.
+func albumTitleMaxLength(job config.FolderUploadJob) int {
+	if job.AlbumTitles == nil {
+		return 0
+	}
+	return job.AlbumTitles.MaxLength
+}
+
+// room returns the number of media items that can be added to the album.
+func (r *albumResolver) room(albumID string) int64 {
+	return photos.MaxAlbumItems - r.itemCounts[albumID]
+}
+
+// track counts the media items added to the album, and keeps the count for the next executions.
+func (r *albumResolver) track(albumID string, n int) error {
+	if r.itemCounts == nil {
+		r.itemCounts = make(map[string]int64)
+	}
+	r.itemCounts[albumID] += int64(n)
+	if r.counts == nil {
+		return nil
+	}
+	return r.counts.Set(albumID, r.itemCounts[albumID])
+}
+
+// create creates an album with the given title and keeps it for later use.
+func (r *albumResolver) create(ctx context.Context, title string) (string, bool, error) {
+	album, err := r.service.Create(ctx, title)
+	if err != nil {
+		return "", false, err
+	}
+
+	r.albumsByTitle[title] = append(r.albumsByTitle[title], album.ID)
+	r.itemCounts[album.ID] = 0
+	return album.ID, true, nil
+}
+
+// addToExtraAlbums adds the uploaded media items of the files to their extra albums. New albums are enriched like
+// the albums where the files are uploaded.
+// It returns the files that could not be added to all their extra albums.
+func (r *albumResolver) addToExtraAlbums(ctx context.Context, logger log.Logger, enricher albumEnricher, job config.FolderUploadJob, files []upload.FileItem, mediaItemIDs map[string]string) map[string]bool {
+	failedFiles := make(map[string]bool)
+
+	groups := upload.GroupByExtraAlbum(files)
+	albumNames := make([]string, 0, len(groups))
+	for albumName := range groups {
+		albumNames = append(albumNames, albumName)
+	}
+	sort.Strings(albumNames)
+
+	for _, albumName := range albumNames {
+		items := groups[albumName]
+		for len(items) > 0 {
+			albumID := job.AlbumMap[albumName]
+			if albumID == "" {
+				var err error
+				albumID, err = resolveAlbum(ctx, logger, r, enricher, job, albumName, filepath.Dir(items[0].Path))
+				if err != nil {
+					logger.Failf("Unable to get or create album '%s': %s", albumName, err)
+					for _, item := range items {
+						failedFiles[item.Path] = true
+					}
+					break
+				}
+			}
+
+			// Mapped albums are not split.
+			n := len(items)
+			if job.AlbumMap[albumName] == "" {
+				n = int(min(int64(n), r.room(albumID)))
+			}
+			r.addMediaItems(ctx, logger, albumID, albumName, items[:n], mediaItemIDs, failedFiles)
+			items = items[n:]
+		}
+	}
+
+	return failedFiles
+}
+
+// addMediaItems adds the media items of the files to the album in batches. Files that could not be added are set
+// in failedFiles.
+func (r *albumResolver) addMediaItems(ctx context.Context, logger log.Logger, albumID string, albumName string, items []upload.FileItem, mediaItemIDs map[string]string, failedFiles map[string]bool) {
+	for start := 0; start < len(items); start += photos.MaxMediaItemsPerBatch {
+		batch := items[start:min(start+photos.MaxMediaItemsPerBatch, len(items))]
+
+		ids := make([]string, 0, len(batch))
+		for _, item := range batch {
+			ids = append(ids, mediaItemIDs[item.Path])
+		}
+
+		logger.Debugf("Adding %d media items to album '%s'.", len(ids), albumName)
+		if err := r.service.AddMediaItems(ctx, albumID, ids); err != nil {
+			logger.Failf("Unable to add media items to album '%s': %s", albumName, err)
+			for _, item := range batch {
+				failedFiles[item.Path] = true
+			}
+			continue
+		}
+		if err := r.track(albumID, len(ids)); err != nil {
+			logger.Warnf("Unable to keep the number of items of album '%s': %s", albumName, err)
+		}
+	}
+}
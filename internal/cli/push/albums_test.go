@@ -0,0 +1,399 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/albums"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/config"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/mock"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/photos"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/upload"
+)
+
+func TestAlbumResolver_Resolve(t *testing.T) {
+	existingAlbums := []albums.Album{
+		{ID: "id-unique", Title: "unique"},
+		{ID: "id-dup-1", Title: "dup"},
+		{ID: "id-dup-2", Title: "dup"},
+		{ID: "id-dup-3", Title: "dup (2)"},
+		{ID: "id-dup-4", Title: "dup (2)"},
+		{ID: "id-dup-5", Title: "dup (3)"},
+	}
+
+	testCases := []struct {
+		name        string
+		title       string
+		policy      string
+		want        string
+		wantCreated bool
+		errExpected bool
+	}{
+		{name: "empty title", title: "", policy: "", want: ""},
+		{name: "reuse-first with unique title", title: "unique", policy: "reuse-first", want: "id-unique"},
+		{name: "reuse-first with duplicated title", title: "dup", policy: "reuse-first", want: "id-dup-1"},
+		{name: "reuse-first by default", title: "dup", policy: "", want: "id-dup-1"},
+		{name: "reuse-first with new title", title: "new", policy: "reuse-first", want: "created:new", wantCreated: true},
+		{name: "error with unique title", title: "unique", policy: "error", want: "id-unique"},
+		{name: "error with duplicated title", title: "dup", policy: "error", errExpected: true},
+		{name: "error with new title", title: "new", policy: "error", want: "created:new", wantCreated: true},
+		{name: "create-suffixed with unique title", title: "unique", policy: "create-suffixed", want: "id-unique"},
+		{name: "create-suffixed with duplicated title", title: "dup", policy: "create-suffixed", want: "id-dup-5"},
+		{name: "create-suffixed with new title", title: "new", policy: "create-suffixed", want: "created:new", wantCreated: true},
+		{name: "invalid policy", title: "unique", policy: "foo", errExpected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newAlbumResolver(&mock.AlbumsService{
+				ListFn: func(ctx context.Context) ([]albums.Album, error) {
+					return existingAlbums, nil
+				},
+				CreateFn: func(ctx context.Context, title string) (*albums.Album, error) {
+					return &albums.Album{ID: "created:" + title, Title: title}, nil
+				},
+			}, nil)
+
+			got, created, err := r.resolve(context.Background(), tc.title, tc.policy)
+			if tc.errExpected {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.wantCreated, created)
+		})
+	}
+}
+
+func TestAlbumResolver_ResolveCreatesSuffixedAlbum(t *testing.T) {
+	r := newAlbumResolver(&mock.AlbumsService{
+		ListFn: func(ctx context.Context) ([]albums.Album, error) {
+			return []albums.Album{{ID: "id-1", Title: "dup"}, {ID: "id-2", Title: "dup"}}, nil
+		},
+		CreateFn: func(ctx context.Context, title string) (*albums.Album, error) {
+			return &albums.Album{ID: "created:" + title, Title: title}, nil
+		},
+	}, nil)
+
+	got, created, err := r.resolve(context.Background(), "dup", createSuffixedPolicy)
+	assert.NoError(t, err)
+	assert.Equal(t, "created:dup (2)", got)
+	assert.True(t, created)
+
+	// The created album is reused afterward.
+	got, created, err = r.resolve(context.Background(), "dup", createSuffixedPolicy)
+	assert.NoError(t, err)
+	assert.Equal(t, "created:dup (2)", got)
+	assert.False(t, created)
+}
+
+func TestAlbumResolver_ResolveListsAlbumsOnce(t *testing.T) {
+	calls := 0
+	r := newAlbumResolver(&mock.AlbumsService{
+		ListFn: func(ctx context.Context) ([]albums.Album, error) {
+			calls++
+			return []albums.Album{{ID: "id-1", Title: "foo"}}, nil
+		},
+	}, nil)
+
+	for i := 0; i < 3; i++ {
+		_, _, err := r.resolve(context.Background(), "foo", reuseFirstPolicy)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, calls)
+}
+
+func TestAlbumResolver_ResolveReturnsErrorWhenListFails(t *testing.T) {
+	r := newAlbumResolver(&mock.AlbumsService{
+		ListFn: func(ctx context.Context) ([]albums.Album, error) {
+			return nil, errors.New("network error")
+		},
+	}, nil)
+
+	_, _, err := r.resolve(context.Background(), "foo", reuseFirstPolicy)
+	assert.Error(t, err)
+}
+
+func TestAlbumResolver_DryRunResolve(t *testing.T) {
+	// Albums are not created, so CreateFn is not set.
+	r := newAlbumResolver(&mock.AlbumsService{
+		ListFn: func(ctx context.Context) ([]albums.Album, error) {
+			return []albums.Album{{ID: "id-family", Title: "Family"}}, nil
+		},
+	}, nil)
+
+	testCases := []struct {
+		title string
+		want  bool
+	}{
+		{title: "Family", want: false},
+		{title: "Summer", want: true},
+		{title: "Summer", want: false},
+	}
+
+	for _, tc := range testCases {
+		got, err := r.dryRunResolve(context.Background(), tc.title)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.want, got, tc.title)
+	}
+}
+
+func TestAlbumResolver_AddToExtraAlbums(t *testing.T) {
+	added := make(map[string][]string)
+	r := newAlbumResolver(&mock.AlbumsService{
+		ListFn: func(ctx context.Context) ([]albums.Album, error) {
+			return []albums.Album{{ID: "id-family", Title: "Family"}, {ID: "id-broken", Title: "Broken"}}, nil
+		},
+		AddMediaItemsFn: func(ctx context.Context, albumId string, mediaItemIds []string) error {
+			if albumId == "id-broken" {
+				return errors.New("network error")
+			}
+			added[albumId] = append(added[albumId], mediaItemIds...)
+			return nil
+		},
+	}, nil)
+
+	job := config.FolderUploadJob{AlbumMap: map[string]string{"Trip": "id-trip"}}
+	files := []upload.FileItem{
+		{Path: "file1.jpg", ExtraAlbumNames: []string{"Family", "Trip"}},
+		{Path: "file2.jpg", ExtraAlbumNames: []string{"Family", "Broken"}},
+		{Path: "file3.jpg"},
+	}
+	mediaItemIDs := map[string]string{"file1.jpg": "item-1", "file2.jpg": "item-2", "file3.jpg": "item-3"}
+
+	failedFiles := r.addToExtraAlbums(context.Background(), &mock.Logger{}, &fakeEnricher{}, job, files, mediaItemIDs)
+
+	assert.Equal(t, map[string]bool{"file2.jpg": true}, failedFiles)
+	assert.ElementsMatch(t, []string{"item-1", "item-2"}, added["id-family"])
+	assert.Equal(t, []string{"item-1"}, added["id-trip"])
+}
+
+func TestAlbumResolver_AddToExtraAlbumsEnrichesNewAlbums(t *testing.T) {
+	folder := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "README.txt"), []byte("Our summer trip."), 0600))
+
+	r := newAlbumResolver(&mock.AlbumsService{
+		ListFn: func(ctx context.Context) ([]albums.Album, error) {
+			return []albums.Album{{ID: "id-family", Title: "Family"}}, nil
+		},
+		CreateFn: func(ctx context.Context, title string) (*albums.Album, error) {
+			return &albums.Album{ID: "id-" + title, Title: title}, nil
+		},
+		AddMediaItemsFn: func(ctx context.Context, albumId string, mediaItemIds []string) error {
+			return nil
+		},
+	}, nil)
+
+	job := config.FolderUploadJob{AlbumEnrichments: &config.AlbumEnrichments{NoteFile: "README.txt"}}
+	files := []upload.FileItem{{Path: filepath.Join(folder, "file1.jpg"), ExtraAlbumNames: []string{"Family", "Summer"}}}
+	mediaItemIDs := map[string]string{files[0].Path: "item-1"}
+
+	e := &fakeEnricher{}
+	failedFiles := r.addToExtraAlbums(context.Background(), &mock.Logger{}, e, job, files, mediaItemIDs)
+
+	assert.Empty(t, failedFiles)
+	// Only the new album is enriched.
+	assert.Equal(t, []string{"Our summer trip."}, e.texts)
+}
+
+func TestAlbumResolver_AddToExtraAlbumsInBatches(t *testing.T) {
+	var batches []int
+	r := newAlbumResolver(&mock.AlbumsService{
+		ListFn: func(ctx context.Context) ([]albums.Album, error) {
+			return []albums.Album{{ID: "id-family", Title: "Family"}}, nil
+		},
+		AddMediaItemsFn: func(ctx context.Context, albumId string, mediaItemIds []string) error {
+			batches = append(batches, len(mediaItemIds))
+			return nil
+		},
+	}, nil)
+
+	var files []upload.FileItem
+	mediaItemIDs := make(map[string]string)
+	for i := 0; i < 120; i++ {
+		path := fmt.Sprintf("file%d.jpg", i)
+		files = append(files, upload.FileItem{Path: path, ExtraAlbumNames: []string{"Family"}})
+		mediaItemIDs[path] = fmt.Sprintf("item-%d", i)
+	}
+
+	failedFiles := r.addToExtraAlbums(context.Background(), &mock.Logger{}, &fakeEnricher{}, config.FolderUploadJob{}, files, mediaItemIDs)
+
+	assert.Empty(t, failedFiles)
+	assert.Equal(t, []int{50, 50, 20}, batches)
+}
+
+func TestAlbumResolver_ResolveWithRoom(t *testing.T) {
+	existingAlbums := []albums.Album{
+		{ID: "id-2023", Title: "2023", TotalMediaItems: photos.MaxAlbumItems},
+		{ID: "id-2023-2", Title: "2023 (2)", TotalMediaItems: photos.MaxAlbumItems},
+		{ID: "id-2023-3", Title: "2023 (3)", TotalMediaItems: 10},
+		{ID: "id-2024", Title: "2024", TotalMediaItems: photos.MaxAlbumItems},
+		{ID: "id-2025", Title: "2025", TotalMediaItems: photos.MaxAlbumItems - 1},
+	}
+
+	testCases := []struct {
+		name        string
+		title       string
+		suffix      string
+		maxLength   int
+		want        string
+		wantCreated bool
+	}{
+		{name: "album with room", title: "2025", want: "id-2025"},
+		{name: "reuses next album with room", title: "2023", want: "id-2023-3"},
+		{name: "creates next album", title: "2024", want: "created:2024 (2)", wantCreated: true},
+		{name: "creates next album using suffix", title: "2024", suffix: " - part %_part%", want: "created:2024 - part 2", wantCreated: true},
+		{name: "new title", title: "2026", want: "created:2026", wantCreated: true},
+		{name: "truncates title to leave room for suffix", title: "2024", maxLength: 6, want: "created:2… (2)", wantCreated: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newAlbumResolver(&mock.AlbumsService{
+				ListFn: func(ctx context.Context) ([]albums.Album, error) {
+					return existingAlbums, nil
+				},
+				CreateFn: func(ctx context.Context, title string) (*albums.Album, error) {
+					return &albums.Album{ID: "created:" + title, Title: title}, nil
+				},
+			}, nil)
+
+			got, created, err := r.resolveWithRoom(context.Background(), tc.title, reuseFirstPolicy, tc.suffix, tc.maxLength)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.wantCreated, created)
+		})
+	}
+}
+
+func TestAlbumResolver_ResolveWithRoomTracksAddedItems(t *testing.T) {
+	r := newAlbumResolver(&mock.AlbumsService{
+		ListFn: func(ctx context.Context) ([]albums.Album, error) {
+			return []albums.Album{{ID: "id-2024", Title: "2024", TotalMediaItems: photos.MaxAlbumItems - 1}}, nil
+		},
+		CreateFn: func(ctx context.Context, title string) (*albums.Album, error) {
+			return &albums.Album{ID: "created:" + title, Title: title}, nil
+		},
+	}, nil)
+
+	got, _, err := r.resolveWithRoom(context.Background(), "2024", reuseFirstPolicy, "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "id-2024", got)
+
+	require.NoError(t, r.track(got, 1))
+
+	got, created, err := r.resolveWithRoom(context.Background(), "2024", reuseFirstPolicy, "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "created:2024 (2)", got)
+	assert.True(t, created)
+}
+
+func TestAlbumResolver_ResolveWithRoomReusesSplitAlbums(t *testing.T) {
+	existingAlbums := []albums.Album{
+		{ID: "id-2024", Title: "2024", TotalMediaItems: photos.MaxAlbumItems},
+		{ID: "id-2024-2", Title: "2024 (2)", TotalMediaItems: 10},
+		{ID: "id-2024-2-dup", Title: "2024 (2)", TotalMediaItems: 10},
+	}
+
+	for _, policy := range []string{createSuffixedPolicy, errorPolicy} {
+		t.Run(policy, func(t *testing.T) {
+			r := newAlbumResolver(&mock.AlbumsService{
+				ListFn: func(ctx context.Context) ([]albums.Album, error) {
+					return existingAlbums, nil
+				},
+				CreateFn: func(ctx context.Context, title string) (*albums.Album, error) {
+					return &albums.Album{ID: "created:" + title, Title: title}, nil
+				},
+			}, nil)
+
+			got, created, err := r.resolveWithRoom(context.Background(), "2024", policy, "", 0)
+			assert.NoError(t, err)
+			assert.Equal(t, "id-2024-2", got)
+			assert.False(t, created)
+		})
+	}
+}
+
+func TestAlbumResolver_ResolveWithRoomUsesKeptCounts(t *testing.T) {
+	counts := memoryCounts{
+		// The API returns an outdated count after adding items to the album.
+		"id-2024": photos.MaxAlbumItems,
+		// The API count is more recent than the kept one, so it's used.
+		"id-2025": 10,
+	}
+	r := newAlbumResolver(&mock.AlbumsService{
+		ListFn: func(ctx context.Context) ([]albums.Album, error) {
+			return []albums.Album{
+				{ID: "id-2024", Title: "2024", TotalMediaItems: 10},
+				{ID: "id-2025", Title: "2025", TotalMediaItems: photos.MaxAlbumItems},
+			}, nil
+		},
+		CreateFn: func(ctx context.Context, title string) (*albums.Album, error) {
+			return &albums.Album{ID: "created:" + title, Title: title}, nil
+		},
+	}, counts)
+
+	got, created, err := r.resolveWithRoom(context.Background(), "2024", reuseFirstPolicy, "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "created:2024 (2)", got)
+	assert.True(t, created)
+
+	got, created, err = r.resolveWithRoom(context.Background(), "2025", reuseFirstPolicy, "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "created:2025 (2)", got)
+	assert.True(t, created)
+
+	require.NoError(t, r.track("created:2024 (2)", 2))
+	assert.Equal(t, int64(2), counts["created:2024 (2)"])
+}
+
+// memoryCounts is an albumCounter keeping the counts in memory.
+type memoryCounts map[string]int64
+
+func (c memoryCounts) Get(albumID string) (int64, bool) {
+	n, ok := c[albumID]
+	return n, ok
+}
+
+func (c memoryCounts) Set(albumID string, n int64) error {
+	c[albumID] = n
+	return nil
+}
+
+func TestAlbumResolver_AddToExtraAlbumsSplitsFullAlbums(t *testing.T) {
+	added := make(map[string][]string)
+	r := newAlbumResolver(&mock.AlbumsService{
+		ListFn: func(ctx context.Context) ([]albums.Album, error) {
+			return []albums.Album{{ID: "id-family", Title: "Family", TotalMediaItems: photos.MaxAlbumItems - 2}}, nil
+		},
+		CreateFn: func(ctx context.Context, title string) (*albums.Album, error) {
+			return &albums.Album{ID: "created:" + title, Title: title}, nil
+		},
+		AddMediaItemsFn: func(ctx context.Context, albumId string, mediaItemIds []string) error {
+			added[albumId] = append(added[albumId], mediaItemIds...)
+			return nil
+		},
+	}, nil)
+
+	var files []upload.FileItem
+	mediaItemIDs := make(map[string]string)
+	for i := 0; i < 5; i++ {
+		path := fmt.Sprintf("file%d.jpg", i)
+		files = append(files, upload.FileItem{Path: path, ExtraAlbumNames: []string{"Family"}})
+		mediaItemIDs[path] = fmt.Sprintf("item-%d", i)
+	}
+
+	failedFiles := r.addToExtraAlbums(context.Background(), &mock.Logger{}, &fakeEnricher{}, config.FolderUploadJob{}, files, mediaItemIDs)
+
+	assert.Empty(t, failedFiles)
+	assert.Equal(t, []string{"item-0", "item-1"}, added["id-family"])
+	assert.Equal(t, []string{"item-2", "item-3", "item-4"}, added["created:Family (2)"])
+}
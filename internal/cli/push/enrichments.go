@@ -0,0 +1,79 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hjson/hjson-go/v4"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/config"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/log"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/photos"
+)
+
+// albumEnricher represents a service to add enrichments to albums.
+type albumEnricher interface {
+	AddTextEnrichment(ctx context.Context, albumID string, text string) error
+	AddLocationEnrichment(ctx context.Context, albumID string, location photos.Location) error
+}
+
+// enrichAlbum adds the configured enrichments to the album, reading the files in the given folder.
+// Missing files are skipped.
+func enrichAlbum(ctx context.Context, logger log.Logger, service albumEnricher, options *config.AlbumEnrichments, albumID string, folder string) error {
+	if options == nil {
+		return nil
+	}
+
+	if options.LocationFile != "" {
+		location, err := readLocationFile(filepath.Join(folder, options.LocationFile))
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			logger.Debugf("Location file not found in '%s', skipping location enrichment.", folder)
+		case err != nil:
+			return err
+		default:
+			if err := service.AddLocationEnrichment(ctx, albumID, location); err != nil {
+				return err
+			}
+		}
+	}
+
+	if options.NoteFile != "" {
+		note, err := os.ReadFile(filepath.Join(folder, options.NoteFile))
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			logger.Debugf("Note file not found in '%s', skipping text enrichment.", folder)
+		case err != nil:
+			return err
+		case strings.TrimSpace(string(note)) == "":
+			logger.Debugf("Note file in '%s' is empty, skipping text enrichment.", folder)
+		default:
+			if err := service.AddTextEnrichment(ctx, albumID, strings.TrimSpace(string(note))); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// readLocationFile returns the location in an HJSON file like:
+//
+//	{ Name: "Lisbon", Latitude: 38.7223, Longitude: -9.1393 }
+func readLocationFile(filename string) (photos.Location, error) {
+	var location photos.Location
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return location, err
+	}
+	if err := hjson.Unmarshal(b, &location); err != nil {
+		return location, err
+	}
+	if location.Name == "" {
+		return location, errors.New("location name could not be empty in " + filename)
+	}
+	return location, nil
+}
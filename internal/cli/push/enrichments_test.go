@@ -0,0 +1,67 @@
+package push
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/config"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/mock"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/photos"
+)
+
+type fakeEnricher struct {
+	texts     []string
+	locations []photos.Location
+}
+
+func (e *fakeEnricher) AddTextEnrichment(ctx context.Context, albumID string, text string) error {
+	e.texts = append(e.texts, text)
+	return nil
+}
+
+func (e *fakeEnricher) AddLocationEnrichment(ctx context.Context, albumID string, location photos.Location) error {
+	e.locations = append(e.locations, location)
+	return nil
+}
+
+func TestEnrichAlbum(t *testing.T) {
+	folder := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "README.txt"), []byte("  Our summer trip.\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "location.hjson"), []byte("{\n  Name: Lisbon\n  Latitude: 38.72\n  Longitude: -9.14\n}"), 0600))
+
+	options := &config.AlbumEnrichments{NoteFile: "README.txt", LocationFile: "location.hjson"}
+
+	e := &fakeEnricher{}
+	err := enrichAlbum(context.Background(), &mock.Logger{}, e, options, "album-id", folder)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Our summer trip."}, e.texts)
+	assert.Equal(t, []photos.Location{{Name: "Lisbon", Latitude: 38.72, Longitude: -9.14}}, e.locations)
+}
+
+func TestEnrichAlbum_SkipsMissingFiles(t *testing.T) {
+	options := &config.AlbumEnrichments{NoteFile: "README.txt", LocationFile: "location.hjson"}
+
+	e := &fakeEnricher{}
+	err := enrichAlbum(context.Background(), &mock.Logger{}, e, options, "album-id", t.TempDir())
+
+	require.NoError(t, err)
+	assert.Empty(t, e.texts)
+	assert.Empty(t, e.locations)
+}
+
+func TestEnrichAlbum_ReturnsErrorWithInvalidLocationFile(t *testing.T) {
+	folder := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "location.hjson"), []byte("{ Latitude: 38.72 }"), 0600))
+
+	options := &config.AlbumEnrichments{LocationFile: "location.hjson"}
+
+	err := enrichAlbum(context.Background(), &mock.Logger{}, &fakeEnricher{}, options, "album-id", folder)
+
+	assert.Error(t, err)
+}
@@ -6,13 +6,15 @@ import (
 	"github.com/gphotosuploader/google-photos-api-client-go/v3/uploader"
 	"github.com/gphotosuploader/gphotos-uploader-cli/internal/app"
 	"github.com/gphotosuploader/gphotos-uploader-cli/internal/cli/flags"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/config"
 	"github.com/gphotosuploader/gphotos-uploader-cli/internal/feedback"
-	"github.com/gphotosuploader/gphotos-uploader-cli/internal/filter"
 	"github.com/gphotosuploader/gphotos-uploader-cli/internal/log"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/photos"
 	"github.com/gphotosuploader/gphotos-uploader-cli/internal/upload"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"net/http"
+	"path/filepath"
 )
 
 // PushCmd holds the required data for the push cmd
@@ -58,6 +60,9 @@ func (cmd *PushCmd) Run(cobraCmd *cobra.Command, args []string) error {
 		cli.Logger.Info("[DRY-RUN] Running in dry run mode. No file will be uploaded.")
 	}
 
+	albumsResolver := newAlbumResolver(photosService.Albums, cli.AlbumCounter)
+	albumsService := photos.NewAlbumsService(cli.Client)
+
 	// launch all folder upload jobs
 	for _, config := range cli.Config.Jobs {
 
@@ -72,21 +77,11 @@ func (cmd *PushCmd) Run(cobraCmd *cobra.Command, args []string) error {
 			config.Album = "auto:" + config.CreateAlbums
 		}
 
-		sourceFolder := config.SourceFolder
-
-		filterFiles, err := filter.Compile(config.IncludePatterns, config.ExcludePatterns)
+		folder, err := cli.NewUploadFolderJob(config)
 		if err != nil {
 			return err
 		}
 
-		folder := upload.UploadFolderJob{
-			FileTracker: cli.FileTracker,
-
-			SourceFolder: sourceFolder,
-			Album:        config.Album,
-			Filter:       filterFiles,
-		}
-
 		// get UploadItem{} to be uploaded to Google Photos.
 		itemsToUpload, err := folder.ScanFolder(cli.Logger)
 		if err != nil {
@@ -94,6 +89,11 @@ func (cmd *PushCmd) Run(cobraCmd *cobra.Command, args []string) error {
 			continue
 		}
 
+		// Files paired with already uploaded files are tracked, so they are not checked again.
+		if !cmd.DryRunMode {
+			trackPairedFiles(cli, folder.PairedWithUploaded())
+		}
+
 		totalItems := len(itemsToUpload)
 		var uploadedItems int
 
@@ -101,25 +101,60 @@ func (cmd *PushCmd) Run(cobraCmd *cobra.Command, args []string) error {
 
 		bar := feedback.NewTaskProgressBar("Uploading files...", totalItems, !cmd.Debug)
 
+		// Albums are processed in the order of their first file, so they are created following the files order.
 		itemsGroupedByAlbum := upload.GroupByAlbum(itemsToUpload)
-		for albumName, files := range itemsGroupedByAlbum {
-			albumId, err := getOrCreateAlbum(ctx, photosService.Albums, albumName)
-			if err != nil {
-				cli.Logger.Failf("Unable to create album '%s': %s", albumName, err)
-				continue
+		for _, key := range upload.AlbumKeys(itemsToUpload) {
+			files := itemsGroupedByAlbum[key]
+			albumName := files[0].AlbumName
+			albumId := files[0].AlbumID
+
+			// Albums set by the AlbumMap option are not split.
+			splitAlbums := albumId == "" && albumName != ""
+			if cmd.DryRunMode {
+				// Albums are not created nor enriched in dry run mode.
+				logAlbumsToCreate(ctx, cli.Logger, albumsResolver, config, files)
+			} else if albumId == "" {
+				albumId, err = resolveAlbum(ctx, cli.Logger, albumsResolver, albumsService, config, albumName, filepath.Dir(files[0].Path))
+				if err != nil {
+					cli.Logger.Failf("Unable to get or create album '%s': %s", albumName, err)
+					continue
+				}
 			}
 
-			for _, file := range files {
+			// Files are tracked as uploaded once they have been uploaded to their album.
+			var uploadedFiles []upload.FileItem
+			mediaItemIDs := make(map[string]string)
+
+			for i, file := range files {
 				cli.Logger.Debugf("Processing (%d/%d): %s", uploadedItems+1, totalItems, file)
 
-				if !cmd.DryRunMode {
+				if !cmd.DryRunMode && file.MediaItemID != "" {
+					// The file was uploaded, but it was not added to all its extra albums.
+					uploadedFiles = append(uploadedFiles, file)
+					mediaItemIDs[file.Path] = file.MediaItemID
+				} else if !cmd.DryRunMode {
+					// Full albums are split, so the rest of the files are uploaded to the next album.
+					if splitAlbums && albumsResolver.room(albumId) <= 0 {
+						albumId, err = resolveAlbum(ctx, cli.Logger, albumsResolver, albumsService, config, albumName, filepath.Dir(file.Path))
+						if err != nil {
+							// The rest of the files can't be uploaded, so they are counted as failed.
+							cli.Logger.Failf("Unable to get or create album '%s': %s", albumName, err)
+							for _, failed := range files[i:] {
+								cli.Logger.Failf("Error processing %s: no album to upload it", failed)
+							}
+							bar.Add(len(files) - i)
+							break
+						}
+					}
+
 					// Upload the file and add it to PhotosService.
-					_, err := photosService.UploadToAlbum(ctx, albumId, file.Path)
+					mediaItem, err := photosService.UploadToAlbum(ctx, albumId, file.Path)
 
 					// Check if the Google Photos daily quota has been exceeded.
 					var e *gphotos.ErrDailyQuotaExceeded
 					if errors.As(err, &e) {
 						cli.Logger.Failf("returning 'quota exceeded' error")
+						completeUploads(ctx, cli, albumsResolver, albumsService, config, uploadedFiles, mediaItemIDs)
 						return err
 					}
 
@@ -128,21 +163,18 @@ func (cmd *PushCmd) Run(cobraCmd *cobra.Command, args []string) error {
 						continue
 					}
 
-					// Mark the file as uploaded in the FileTracker.
-					if err := cli.FileTracker.MarkAsUploaded(file.Path); err != nil {
-						cli.Logger.Warnf("Tracking file as uploaded failed: file=%s, error=%v", file, err)
-					}
-
-					if config.DeleteAfterUpload {
-						if err := file.Remove(); err != nil {
-							cli.Logger.Errorf("Deletion request failed: file=%s, err=%v", file, err)
-						}
+					if err := albumsResolver.track(albumId, 1); err != nil {
+						cli.Logger.Warnf("Unable to keep the number of items of album '%s': %s", albumName, err)
 					}
+					uploadedFiles = append(uploadedFiles, file)
+					mediaItemIDs[file.Path] = mediaItem.ID
 				}
 
 				bar.Add(1)
 				uploadedItems++
 			}
+
+			uploadedItems -= completeUploads(ctx, cli, albumsResolver, albumsService, config, uploadedFiles, mediaItemIDs)
 		}
 
 		bar.Finish()
@@ -152,6 +184,90 @@ func (cmd *PushCmd) Run(cobraCmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// resolveAlbum returns the ID of an album with room for more files, creating it if needed.
+// New albums are enriched using the files in the given folder.
+func resolveAlbum(ctx context.Context, logger log.Logger, resolver *albumResolver, enricher albumEnricher, job config.FolderUploadJob, albumName string, folder string) (string, error) {
+	albumId, created, err := resolver.resolveWithRoom(ctx, albumName, job.DuplicateAlbumPolicy, job.AlbumSplitSuffix, albumTitleMaxLength(job))
+	if err != nil {
+		return "", err
+	}
+
+	if created {
+		if err := enrichAlbum(ctx, logger, enricher, job.AlbumEnrichments, albumId, folder); err != nil {
+			logger.Warnf("Unable to add enrichments to album '%s': %s", albumName, err)
+		}
+	}
+	return albumId, nil
+}
+
+// logAlbumsToCreate logs the albums of the files that would be created, without creating them.
+func logAlbumsToCreate(ctx context.Context, logger log.Logger, resolver *albumResolver, job config.FolderUploadJob, files []upload.FileItem) {
+	for _, file := range files {
+		titles := file.ExtraAlbumNames
+		if file.AlbumID == "" {
+			titles = append([]string{file.AlbumName}, titles...)
+		}
+		for _, title := range titles {
+			if title == "" || job.AlbumMap[title] != "" {
+				continue
+			}
+			created, err := resolver.dryRunResolve(ctx, title)
+			if err != nil {
+				logger.Failf("Unable to get album '%s': %s", title, err)
+				continue
+			}
+			if created {
+				logger.Infof("[DRY-RUN] Album '%s' would be created.", title)
+			}
+		}
+	}
+}
+
+// completeUploads adds the uploaded files to their extra albums and tracks them as uploaded.
+// Files that could not be added to all their extra albums are tracked with their media item, so they will be added
+// to them in the next execution without uploading them again. It returns the number of these files.
+func completeUploads(ctx context.Context, cli *app.App, resolver *albumResolver, enricher albumEnricher, job config.FolderUploadJob, files []upload.FileItem, mediaItemIDs map[string]string) int {
+	failedFiles := resolver.addToExtraAlbums(ctx, cli.Logger, enricher, job, files, mediaItemIDs)
+
+	for _, file := range files {
+		// Mark the file as uploaded in the FileTracker.
+		var err error
+		if failedFiles[file.Path] {
+			err = cli.FileTracker.MarkAsPendingAlbums(file.Path, mediaItemIDs[file.Path])
+		} else {
+			err = cli.FileTracker.MarkAsUploaded(file.Path)
+		}
+		if err != nil {
+			cli.Logger.Warnf("Tracking file as uploaded failed: file=%s, error=%v", file, err)
+		}
+
+		// Mark its siblings skipped by the pairing policies, so they are not checked again.
+		for _, sibling := range file.PairedFiles {
+			if err := cli.FileTracker.MarkAsPaired(sibling, file.Path); err != nil {
+				cli.Logger.Warnf("Tracking file as paired failed: file=%s, error=%v", sibling, err)
+			}
+		}
+
+		// Files pending to be added to their extra albums are kept, so they are found in the next execution.
+		if job.DeleteAfterUpload && !failedFiles[file.Path] {
+			if err := file.Remove(); err != nil {
+				cli.Logger.Errorf("Deletion request failed: file=%s, err=%v", file, err)
+			}
+		}
+	}
+
+	return len(failedFiles)
+}
+
+// trackPairedFiles marks the files as paired with their sibling in the FileTracker.
+func trackPairedFiles(cli *app.App, pairedFiles map[string]string) {
+	for file, sibling := range pairedFiles {
+		if err := cli.FileTracker.MarkAsPaired(file, sibling); err != nil {
+			cli.Logger.Warnf("Tracking file as paired failed: file=%s, error=%v", file, err)
+		}
+	}
+}
+
 func newPhotosService(client *http.Client, sessionTracker app.UploadSessionTracker, logger log.Logger) (*gphotos.Client, error) {
 	u, err := uploader.NewResumableUploader(client)
 	if err != nil {
@@ -170,22 +286,3 @@ func newPhotosService(client *http.Client, sessionTracker app.UploadSessionTrack
 
 	return photos, nil
 }
-
-// getOrCreateAlbum returns the created (or existent) album in PhotosService.
-func getOrCreateAlbum(ctx context.Context, service gphotos.AlbumsService, title string) (string, error) {
-	// Returns if empty to avoid a PhotosService call.
-	if title == "" {
-		return "", nil
-	}
-
-	if album, err := service.GetByTitle(ctx, title); err == nil {
-		return album.ID, nil
-	}
-
-	album, err := service.Create(ctx, title)
-	if err != nil {
-		return "", err
-	}
-
-	return album.ID, nil
-}
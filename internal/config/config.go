@@ -8,8 +8,11 @@ import (
 	"github.com/gphotosuploader/gphotos-uploader-cli/internal/log"
 	"io"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/filter"
 	"github.com/gphotosuploader/gphotos-uploader-cli/internal/upload"
 	"github.com/hjson/hjson-go/v4"
 	"github.com/mitchellh/go-homedir"
@@ -79,6 +82,9 @@ func (c Config) validate(fs afero.Fs, logger log.Logger) error {
 	if err := c.validateAccount(); err != nil {
 		return err
 	}
+	if err := filter.ValidateTags(c.PatternTags); err != nil {
+		return fmt.Errorf("option PatternTags is invalid, %w", err)
+	}
 	if err := c.validateJobs(fs, logger); err != nil {
 		return err
 	}
@@ -160,6 +166,85 @@ func (c Config) validateJob(fs afero.Fs, job FolderUploadJob, logger log.Logger)
 		return err
 	}
 
+	if err := validateAlbumRules(job.AlbumRules, logger); err != nil {
+		return err
+	}
+
+	if err := validateFilterRules(job, c.PatternTags); err != nil {
+		return err
+	}
+
+	if err := validateFileConditions(job.FileConditions); err != nil {
+		return err
+	}
+
+	for _, album := range job.Albums {
+		if album == "" {
+			return errors.New("option Albums is invalid, values could not be empty")
+		}
+		if err := validateAlbumOption(album, logger); err != nil {
+			return err
+		}
+	}
+
+	if job.AlbumNameLocale != "" {
+		if err := upload.ValidateAlbumNameLocale(job.AlbumNameLocale); err != nil {
+			return fmt.Errorf("option AlbumNameLocale is invalid, %w", err)
+		}
+	}
+
+	if err := validateAlbumMap(job.AlbumMap); err != nil {
+		return err
+	}
+
+	if err := validateDuplicateAlbumPolicy(job.DuplicateAlbumPolicy); err != nil {
+		return err
+	}
+
+	if err := validateAlbumSplitSuffix(job.AlbumSplitSuffix); err != nil {
+		return err
+	}
+
+	if err := validateAlbumTitles(job.AlbumTitles); err != nil {
+		return err
+	}
+
+	if err := validateAlbumEnrichments(job.AlbumEnrichments); err != nil {
+		return err
+	}
+
+	if err := validateAlbumItemsOrder(job.AlbumItemsOrder); err != nil {
+		return err
+	}
+
+	if err := validateDateSources(job.DateSources); err != nil {
+		return err
+	}
+
+	if err := validateRawJpegPairing(job.RawJpegPairing); err != nil {
+		return err
+	}
+
+	if err := validateLivePhotoPairing(job.LivePhotoPairing); err != nil {
+		return err
+	}
+
+	if err := validateEventGap(job.EventGap); err != nil {
+		return err
+	}
+
+	if job.EventAlbumName != "" {
+		if err := upload.ValidateAlbumNameTemplate(job.EventAlbumName); err != nil {
+			return fmt.Errorf("option EventAlbumName is invalid, %w", err)
+		}
+	}
+
+	for _, pattern := range job.FilenameDatePatterns {
+		if err := upload.ValidateFilenameDatePattern(pattern); err != nil {
+			return fmt.Errorf("option FilenameDatePatterns is invalid, %w", err)
+		}
+	}
+
 	if err := c.checkDeprecatedCreateAlbums(job, logger); err != nil {
 		return err
 	}
@@ -222,6 +307,9 @@ func validateAlbumOption(value string, logger log.Logger) error {
 	case "name":
 		return validateNameOption()
 	case "auto":
+		if value == upload.EventAlbumOption {
+			return nil
+		}
 		return fmt.Errorf("option Album is invalid, '%s", value)
 	case "template":
 		return validateTemplateOption(after)
@@ -241,6 +329,207 @@ func validateTemplateOption(after string) error {
 	return nil
 }
 
+// validateAlbumMap checks that all the entries in the AlbumMap option have a key and an album ID.
+func validateAlbumMap(albumMap map[string]string) error {
+	for key, albumID := range albumMap {
+		if key == "" {
+			return errors.New("option AlbumMap is invalid, keys could not be empty")
+		}
+		if albumID == "" {
+			return fmt.Errorf("option AlbumMap is invalid, album ID for '%s' could not be empty", key)
+		}
+	}
+	return nil
+}
+
+func validateDuplicateAlbumPolicy(value string) error {
+	switch value {
+	case "", "reuse-first", "error", "create-suffixed":
+		return nil
+	}
+	return fmt.Errorf("option DuplicateAlbumPolicy is invalid, '%s'", value)
+}
+
+// validateAlbumSplitSuffix checks that the AlbumSplitSuffix option includes the number of the album.
+func validateAlbumSplitSuffix(value string) error {
+	if value != "" && !strings.Contains(value, "%_part%") {
+		return fmt.Errorf("option AlbumSplitSuffix is invalid, '%s' must include %%_part%%", value)
+	}
+	return nil
+}
+
+func validateAlbumItemsOrder(value string) error {
+	switch value {
+	case "", "oldest-first", "newest-first", "none":
+		return nil
+	}
+	return fmt.Errorf("option AlbumItemsOrder is invalid, '%s'", value)
+}
+
+func validateRawJpegPairing(value string) error {
+	switch value {
+	case "", "upload-both", "prefer-jpeg", "prefer-raw":
+		return nil
+	}
+	return fmt.Errorf("option RawJpegPairing is invalid, '%s'", value)
+}
+
+func validateLivePhotoPairing(value string) error {
+	switch value {
+	case "", "upload-both", "skip-video":
+		return nil
+	}
+	return fmt.Errorf("option LivePhotoPairing is invalid, '%s'", value)
+}
+
+// validateEventGap checks that the EventGap option is a positive duration.
+func validateEventGap(value string) error {
+	if value == "" {
+		return nil
+	}
+	gap, err := time.ParseDuration(value)
+	if err != nil || gap <= 0 {
+		return fmt.Errorf("option EventGap is invalid, '%s'", value)
+	}
+	return nil
+}
+
+// validateDateSources checks that the DateSources option contains valid and not repeated values.
+func validateDateSources(sources []string) error {
+	seen := make(map[string]bool)
+	for _, source := range sources {
+		switch source {
+		case "metadata", "filename", "mtime":
+		default:
+			return fmt.Errorf("option DateSources is invalid, '%s'", source)
+		}
+		if seen[source] {
+			return fmt.Errorf("option DateSources is invalid, '%s' is repeated", source)
+		}
+		seen[source] = true
+	}
+	return nil
+}
+
+// validateAlbumRules checks that all the album rules have conditions and a valid album.
+func validateAlbumRules(rules []AlbumRule, logger log.Logger) error {
+	for i, rule := range rules {
+		if rule.Pattern == "" && len(rule.Extensions) == 0 && rule.MinSize == "" && rule.MaxSize == "" && rule.CameraModel == "" {
+			return fmt.Errorf("option AlbumRules is invalid, rule %d has no conditions", i+1)
+		}
+		if rule.Album == "" {
+			return fmt.Errorf("option AlbumRules is invalid, rule %d has no album", i+1)
+		}
+		if err := validateAlbumOption(rule.Album, logger); err != nil {
+			return fmt.Errorf("option AlbumRules is invalid, rule %d: %w", i+1, err)
+		}
+		if _, err := filter.Compile([]string{rule.Pattern}, nil); rule.Pattern != "" && err != nil {
+			return fmt.Errorf("option AlbumRules is invalid, rule %d: %w", i+1, err)
+		}
+		for _, size := range []string{rule.MinSize, rule.MaxSize} {
+			if _, err := upload.ParseFileSize(size); size != "" && err != nil {
+				return fmt.Errorf("option AlbumRules is invalid, rule %d: %w", i+1, err)
+			}
+		}
+		if err := upload.ValidateCameraModelPattern(rule.CameraModel); err != nil {
+			return fmt.Errorf("option AlbumRules is invalid, rule %d: invalid camera model '%s'", i+1, rule.CameraModel)
+		}
+	}
+	return nil
+}
+
+// validateFilterRules checks that the FilterRules option is valid and it's not used with the IncludePatterns or
+// ExcludePatterns options.
+func validateFilterRules(job FolderUploadJob, tags map[string][]string) error {
+	if len(job.FilterRules) == 0 {
+		return nil
+	}
+	if slices.ContainsFunc(slices.Concat(job.IncludePatterns, job.ExcludePatterns), func(p string) bool { return p != "" }) {
+		return errors.New("option FilterRules is invalid, it can't be used with IncludePatterns or ExcludePatterns")
+	}
+	if _, err := filter.CompileRules(job.FilterRules, filter.Options{CaseInsensitive: job.CaseInsensitivePatterns, Tags: tags}); err != nil {
+		return fmt.Errorf("option FilterRules is invalid, %w", err)
+	}
+	return nil
+}
+
+// validateFileConditions checks that the sizes, ages and dates of the FileConditions option are valid, and the
+// limits of every condition are in order.
+func validateFileConditions(conditions *FileConditions) error {
+	if conditions == nil {
+		return nil
+	}
+	var sizes [2]int64
+	for i, value := range []string{conditions.MinSize, conditions.MaxSize} {
+		if value == "" {
+			continue
+		}
+		size, err := upload.ParseFileSize(value)
+		if err != nil {
+			return fmt.Errorf("option FileConditions is invalid, %w", err)
+		}
+		sizes[i] = size
+	}
+	if sizes[0] > 0 && sizes[1] > 0 && sizes[0] > sizes[1] {
+		return errors.New("option FileConditions is invalid, MinSize is bigger than MaxSize")
+	}
+
+	for _, value := range []string{conditions.NewerThan, conditions.OlderThan} {
+		if value == "" {
+			continue
+		}
+		if _, err := upload.ParseAge(value); err != nil {
+			return fmt.Errorf("option FileConditions is invalid, %w", err)
+		}
+	}
+
+	var dates [2]time.Time
+	for i, value := range []string{conditions.CapturedFrom, conditions.CapturedTo} {
+		if value == "" {
+			continue
+		}
+		date, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return fmt.Errorf("option FileConditions is invalid, '%s' is not a date like 2024-05-31", value)
+		}
+		dates[i] = date
+	}
+	if !dates[0].IsZero() && !dates[1].IsZero() && dates[0].After(dates[1]) {
+		return errors.New("option FileConditions is invalid, CapturedFrom is after CapturedTo")
+	}
+	return nil
+}
+
+// validateAlbumTitles checks that the maximum length of the titles is valid and replaced strings are not empty.
+func validateAlbumTitles(titles *AlbumTitles) error {
+	if titles == nil {
+		return nil
+	}
+	if titles.MaxLength < 0 || titles.MaxLength > upload.DefaultAlbumTitleMaxLength {
+		return fmt.Errorf("option AlbumTitles is invalid, MaxLength must be between 0 and %d", upload.DefaultAlbumTitleMaxLength)
+	}
+	if _, found := titles.Replacements[""]; found {
+		return errors.New("option AlbumTitles is invalid, replaced strings can't be empty")
+	}
+	return nil
+}
+
+// validateAlbumEnrichments checks that the AlbumEnrichments option uses file names, not paths.
+func validateAlbumEnrichments(enrichments *AlbumEnrichments) error {
+	if enrichments == nil {
+		return nil
+	}
+	if enrichments.NoteFile == "" && enrichments.LocationFile == "" {
+		return errors.New("option AlbumEnrichments is invalid, NoteFile or LocationFile must be set")
+	}
+	for _, name := range []string{enrichments.NoteFile, enrichments.LocationFile} {
+		if name != filepath.Base(name) {
+			return fmt.Errorf("option AlbumEnrichments is invalid, '%s' must be a file name", name)
+		}
+	}
+	return nil
+}
+
 // unmarshalReader unmarshal HJSON data into the provided interface.
 func unmarshalReader(in io.Reader, c interface{}) error {
 	buf := new(bytes.Buffer)
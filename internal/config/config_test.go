@@ -67,6 +67,23 @@ func TestFromFile(t *testing.T) {
 		{"Should success with Album's name option", "testdata/valid-config/configWithAlbumNameOption.hjson", "youremail@domain.com", false},
 		{"Should success with Album's template containing token", "testdata/valid-config/configWithAlbumTemplateToken.hjson", "youremail@domain.com", false},
 		{"Should success without Album option", "testdata/valid-config/configWithoutAlbumOption.hjson", "youremail@domain.com", false},
+		{"Should success with Albums option", "testdata/valid-config/configWithAlbumsOption.hjson", "youremail@domain.com", false},
+		{"Should success with AlbumEnrichments option", "testdata/valid-config/configWithAlbumEnrichmentsOption.hjson", "youremail@domain.com", false},
+		{"Should success with AlbumItemsOrder option", "testdata/valid-config/configWithAlbumItemsOrderOption.hjson", "youremail@domain.com", false},
+		{"Should success with DateSources option", "testdata/valid-config/configWithDateSourcesOption.hjson", "youremail@domain.com", false},
+		{"Should success with AlbumNameLocale option", "testdata/valid-config/configWithAlbumNameLocaleOption.hjson", "youremail@domain.com", false},
+		{"Should success with FilenameDatePatterns option", "testdata/valid-config/configWithFilenameDatePatternsOption.hjson", "youremail@domain.com", false},
+		{"Should success with AlbumRules option", "testdata/valid-config/configWithAlbumRulesOption.hjson", "youremail@domain.com", false},
+		{"Should success with AlbumSplitSuffix option", "testdata/valid-config/configWithAlbumSplitSuffixOption.hjson", "youremail@domain.com", false},
+		{"Should success with AlbumTitles option", "testdata/valid-config/configWithAlbumTitlesOption.hjson", "youremail@domain.com", false},
+		{"Should success with CaseInsensitivePatterns option", "testdata/valid-config/configWithCaseInsensitivePatternsOption.hjson", "youremail@domain.com", false},
+		{"Should success with FilterRules option", "testdata/valid-config/configWithFilterRulesOption.hjson", "youremail@domain.com", false},
+		{"Should success with FileConditions option", "testdata/valid-config/configWithFileConditionsOption.hjson", "youremail@domain.com", false},
+		{"Should success with DetectByContent option", "testdata/valid-config/configWithDetectByContentOption.hjson", "youremail@domain.com", false},
+		{"Should success with PatternTags option", "testdata/valid-config/configWithPatternTagsOption.hjson", "youremail@domain.com", false},
+		{"Should success with RawJpegPairing and LivePhotoPairing options", "testdata/valid-config/configWithPairingOptions.hjson", "youremail@domain.com", false},
+		{"Should success with auto:event Album option", "testdata/valid-config/configWithEventAlbumOption.hjson", "youremail@domain.com", false},
+		{"Should success with AlbumMap option", "testdata/valid-config/configWithAlbumMapOption.hjson", "youremail@domain.com", false},
 
 		{"Should fail if config dir does not exist", "testdata/non-existent/config.hjson", "", true},
 		{"Should fail if Account is invalid", "testdata/invalid-config/EmptyAccount.hjson", "", true},
@@ -81,6 +98,30 @@ func TestFromFile(t *testing.T) {
 		{"Should fail if Album's key is invalid", "testdata/invalid-config/AlbumBadKey.hjson", "", true},
 		{"Should fail if Album's name is invalid", "testdata/invalid-config/AlbumEmptyName.hjson", "", true},
 		{"Should fail if Album's auto value is invalid", "testdata/invalid-config/AlbumBadAutoValue.hjson", "", true},
+		{"Should fail if Albums contains an invalid template", "testdata/invalid-config/AlbumsBadNameTemplate.hjson", "", true},
+		{"Should fail if AlbumMap has an empty album ID", "testdata/invalid-config/AlbumMapEmptyAlbumID.hjson", "", true},
+		{"Should fail if AlbumEnrichments uses a path", "testdata/invalid-config/AlbumEnrichmentsBadNoteFile.hjson", "", true},
+		{"Should fail if DuplicateAlbumPolicy is invalid", "testdata/invalid-config/BadDuplicateAlbumPolicy.hjson", "", true},
+		{"Should fail if AlbumItemsOrder is invalid", "testdata/invalid-config/BadAlbumItemsOrder.hjson", "", true},
+		{"Should fail if DateSources is invalid", "testdata/invalid-config/BadDateSources.hjson", "", true},
+		{"Should fail if AlbumNameLocale is not supported", "testdata/invalid-config/BadAlbumNameLocale.hjson", "", true},
+		{"Should fail if DateSources has repeated values", "testdata/invalid-config/RepeatedDateSources.hjson", "", true},
+		{"Should fail if FilenameDatePatterns has no year", "testdata/invalid-config/FilenameDatePatternsWithoutYear.hjson", "", true},
+		{"Should fail if an album rule has no conditions", "testdata/invalid-config/AlbumRuleWithoutConditions.hjson", "", true},
+		{"Should fail if an album rule has an invalid size", "testdata/invalid-config/BadAlbumRuleSize.hjson", "", true},
+		{"Should fail if an album rule has an invalid album", "testdata/invalid-config/BadAlbumRuleAlbum.hjson", "", true},
+		{"Should fail if AlbumSplitSuffix has no part token", "testdata/invalid-config/BadAlbumSplitSuffix.hjson", "", true},
+		{"Should fail if AlbumTitles MaxLength is too long", "testdata/invalid-config/BadAlbumTitlesMaxLength.hjson", "", true},
+		{"Should fail if FilterRules has an invalid pattern", "testdata/invalid-config/BadFilterRules.hjson", "", true},
+		{"Should fail if FilterRules is used with ExcludePatterns", "testdata/invalid-config/FilterRulesWithExcludePatterns.hjson", "", true},
+		{"Should fail if FileConditions MinSize is bigger than MaxSize", "testdata/invalid-config/BadFileConditionsSizes.hjson", "", true},
+		{"Should fail if FileConditions has an invalid date", "testdata/invalid-config/BadFileConditionsDate.hjson", "", true},
+		{"Should fail if a PatternTags tag references itself", "testdata/invalid-config/PatternTagReferencingItself.hjson", "", true},
+		{"Should fail if PatternTags replaces a built-in tag", "testdata/invalid-config/BuiltInPatternTag.hjson", "", true},
+		{"Should fail if RawJpegPairing is invalid", "testdata/invalid-config/BadRawJpegPairing.hjson", "", true},
+		{"Should fail if LivePhotoPairing is invalid", "testdata/invalid-config/BadLivePhotoPairing.hjson", "", true},
+		{"Should fail if EventGap is not a positive duration", "testdata/invalid-config/BadEventGap.hjson", "", true},
+		{"Should fail if EventAlbumName is invalid", "testdata/invalid-config/BadEventAlbumName.hjson", "", true},
 		{"Should fail if deprecated CreateAlbums option is used", "testdata/invalid-config/DeprecatedCreateAlbumsOption.hjson", "", true},
 		{"Should fail if deprecated Album's auto folderName option is used", "testdata/invalid-config/DeprecatedAlbumAutoFolderNameOption.hjson", "", true},
 		{"Should fail if deprecated Album's auto folderPath option is used", "testdata/invalid-config/DeprecatedAlbumAutoFolderPathOption.hjson", "", true},
@@ -12,6 +12,12 @@ type Config struct {
 	// SecretsBackendType is the type of backend to store secrets.
 	SecretsBackendType string `json:"SecretsBackendType"`
 
+	// PatternTags are tagged patterns that can be used in the patterns of all the jobs, like the built-in
+	// _IMAGE_EXTENSIONS_. Names must be like _NAME_, and patterns can reference other tags.
+	//
+	//   Example: { _PHONE_EXPORTS_: [ "**/DCIM/**", "_HEIF_EXTENSIONS_" ] }
+	PatternTags map[string][]string `json:"PatternTags,omitempty"`
+
 	// Jobs are the source folders to work with.
 	Jobs []FolderUploadJob `json:"Jobs"`
 }
@@ -32,9 +38,10 @@ type FolderUploadJob struct {
 	// Album is the album where objects will be uploaded.
 	// If the Album option is not set, the objects will not be associated with an album in Google Photos.
 	//
-	// These are the valid values: "name:", "auto:", "template".
+	// These are the valid values: "name:", "auto:event", "template".
 	//   "name:" : Followed by the album name in Google Photos (album names are not unique, so the first to match
 	//             will be selected)
+	//   "auto:event" : Groups the objects of every folder in events, see EventGap and EventAlbumName.
 	//   "template": Followed by a template string that can contain the following predefine tokens and functions:
 	//              Tokens:
 	//                 %_folderpath% - full path of the folder containing the file.
@@ -45,6 +52,16 @@ type FolderUploadJob struct {
 	//                 %_year% - year the file was created (in "YYYY" format).
 	//                 %_time% - time the file was created (in "HH:MM:SS" 24-hour format).
 	//                 %_time_en% - time the file was created (in "HH:MM:SS AM/PM" 12-hour format).
+	//                 %_month_name% - name of the month the file was created, see AlbumNameLocale.
+	//                 %_weekday% - name of the day of the week the file was created, see AlbumNameLocale.
+	//                 %_week% - ISO week the file was created (in "WW" format).
+	//                 %_week_year% - year of the ISO week the file was created (in "YYYY" format).
+	//                 %_quarter% - quarter of the year the file was created (from 1 to 4).
+	//                 %_filename% - name of the file without extension.
+	//                 %_extension% - extension of the file, without the dot.
+	//                 %_camera_make% - camera maker, from the EXIF metadata.
+	//                 %_camera_model% - camera model, from the EXIF metadata.
+	//                 %_segment_N% - N-th folder of the file path relative to SourceFolder, starting at 1.
 	//              Functions:
 	//                 $lower(string) - converts the string to lowercase.
 	//                 $upper(string) - converts the string to uppercase.
@@ -53,11 +70,116 @@ type FolderUploadJob struct {
 	//                 $regex(string, regex, replacement) - replaces the string with the regex replacement.
 	//                 $cutLeft(string, length) - cuts the string from the left.
 	//                 $cutRight(string, length) - cuts the string from the right.
+	//                 $replace(string, old, new) - replaces all the occurrences of old with new.
+	//                 $trim(string) - removes leading and trailing spaces, and collapses repeated spaces.
+	//                 $pad(string, length[, char]) - pads the string on the left with char ("0" by default).
+	//                 $if(condition, then[, else]) - returns then if condition is not empty, else otherwise.
+	//                 $ifempty(string, fallback) - returns fallback if the string is empty.
+	//                 $date(format) - formats the date the file was created using YYYY, YY, MMMM, MM, DD, dddd,
+	//                                 HH, mm and ss.
 	//
 	//              Example: "template:%_directory% - %_month%.%_day%.$cutLeft(%_year%,2)"
 
 	Album string `json:"Album,omitempty"`
 
+	// AlbumRules set the album of the objects that match them, instead of the Album option. Rules are checked in
+	// order and the first matching rule is used. The Album option is used for the objects that match no rule.
+	//
+	//   Example: [
+	//     { Pattern: "**/Screenshot*", Album: "name:Screenshots" }
+	//     { Pattern: "WhatsApp/**", Album: "name:WhatsApp" }
+	//   ]
+	AlbumRules []AlbumRule `json:"AlbumRules,omitempty"`
+
+	// Albums are other albums where objects will be added, using the same values as the Album option.
+	// Objects are uploaded once, to the album set by the Album option (or to the first of these if it's not set),
+	// and then they are added to the rest of the albums.
+	//
+	//   Example: [ "name:Family", "template:%_year% - %_directory%" ]
+	Albums []string `json:"Albums,omitempty"`
+
+	// AlbumNameLocale is the language of the month and weekday names in album name templates.
+	//
+	// These are the valid values: "en", "es", "fr", "de", "it", "pt", "nl".
+	//
+	//   Default: "en"
+	AlbumNameLocale string `json:"AlbumNameLocale,omitempty"`
+
+	// AlbumMap maps folders (relative to SourceFolder) or album names to fixed album IDs in Google Photos.
+	// Files in a mapped folder, or whose album name is mapped, are uploaded to that album. The nearest
+	// mapped folder takes precedence over the album name.
+	//
+	//   Example: { "Family/2024": "ALBUM_ID_1", "Summer Trip": "ALBUM_ID_2" }
+	AlbumMap map[string]string `json:"AlbumMap,omitempty"`
+
+	// DuplicateAlbumPolicy sets what to do when several albums in Google Photos share the same name.
+	//
+	// These are the valid values: "reuse-first", "error", "create-suffixed".
+	//   "reuse-first": Uploads to the first album found with that name. It's the default value.
+	//   "error": Fails uploading the files of that album.
+	//   "create-suffixed": Uses the first name of "Name", "Name (2)", "Name (3)"... that is not duplicated,
+	//                      creating it if it does not exist.
+	DuplicateAlbumPolicy string `json:"DuplicateAlbumPolicy,omitempty"`
+
+	// AlbumSplitSuffix is added to the title of full albums to get the next album, like "Title (2)".
+	// Albums can't hold more than 20,000 objects, so objects are added to the next album once an album is full.
+	// It must include the %_part% token, replaced by the number of the album.
+	//
+	//   Default: " (%_part%)"
+	AlbumSplitSuffix string `json:"AlbumSplitSuffix,omitempty"`
+
+	// AlbumTitles sets how album titles are sanitized. If it is not set, titles are truncated to 500 characters.
+	AlbumTitles *AlbumTitles `json:"AlbumTitles,omitempty"`
+
+	// AlbumEnrichments adds enrichments to the albums created by this job, using files in the folder of the
+	// objects uploaded to them. If it is not set, no enrichments are added.
+	AlbumEnrichments *AlbumEnrichments `json:"AlbumEnrichments,omitempty"`
+
+	// AlbumItemsOrder sets the upload order of the objects, so they appear in the albums in that order.
+	// The capture time is calculated using the DateSources option.
+	//
+	// These are the valid values: "oldest-first", "newest-first", "none".
+	//   "oldest-first": Uploads the oldest objects first.
+	//   "newest-first": Uploads the newest objects first.
+	//   "none": Uploads the objects in the order they are found in the SourceFolder. It's the default value.
+	AlbumItemsOrder string `json:"AlbumItemsOrder,omitempty"`
+
+	// DateSources are the sources of the capture time of the objects, in order of preference. The capture time is
+	// used by the album name templates and the AlbumItemsOrder option.
+	// The file modification time is used if none of the sources knows it.
+	//
+	// These are the valid values: "metadata", "filename", "mtime".
+	//   "metadata": Reads the EXIF metadata of JPEG, HEIF and TIFF-based RAW files, and the metadata of
+	//               QuickTime, ISO-BMFF (MP4, MOV, 3GP) and AVCHD (MTS, M2TS) videos.
+	//   "filename": Reads the date from the file name, like "IMG_20210314_101500.jpg". See FilenameDatePatterns.
+	//   "mtime": Uses the file modification time.
+	//
+	//   Default: ["metadata", "filename", "mtime"]
+	DateSources []string `json:"DateSources,omitempty"`
+
+	// EventGap is the time between objects to start a new event, used by the "auto:event" album option.
+	// Objects of every folder are sorted by capture time, and a new event starts after this time without objects.
+	// It's a duration like "6h" or "90m".
+	//
+	//   Default: "8h"
+	EventGap string `json:"EventGap,omitempty"`
+
+	// EventAlbumName is the template of the album names used by the "auto:event" album option. Besides the
+	// template tokens and functions, it can use these tokens:
+	//   %_event_start% - date of the first object of the event (in "YYYY-MM-DD" format).
+	//   %_event_end% - date of the last object of the event (in "YYYY-MM-DD" format).
+	//   %_event_dates% - dates of the event, like "2024-05-01 - 2024-05-03", or a single date for one-day events.
+	//
+	//   Default: "%_directory% %_event_start%"
+	EventAlbumName string `json:"EventAlbumName,omitempty"`
+
+	// FilenameDatePatterns are regular expressions to find dates in file names, used by the "filename" date source.
+	// They are tried before the built-in patterns. Patterns must have the named groups "year", "month" and "day",
+	// and optionally "hour", "minute" and "second".
+	//
+	//   Example: ["^trip-(?P<day>\\d{2})\\.(?P<month>\\d{2})\\.(?P<year>\\d{4})"]
+	FilenameDatePatterns []string `json:"FilenameDatePatterns,omitempty"`
+
 	// CreateAlbums exists to notice users about its deprecation. It should not be used in favor of the Album option.
 	CreateAlbums string `json:"CreateAlbums,omitempty"`
 
@@ -69,4 +191,123 @@ type FolderUploadJob struct {
 
 	// ExcludePatterns are the patterns to exclude files.
 	ExcludePatterns []string `json:"ExcludePatterns"`
+
+	// FilterRules is an ordered list of rules to filter files, used instead of IncludePatterns and ExcludePatterns.
+	// Rules exclude the files matching their pattern, unless they start with '!', that includes them again.
+	// The last matching rule wins. If the first rule is not an include rule, "!_IMAGE_EXTENSIONS_" is used first.
+	//
+	//   Example: [ "!_ALL_FILES_", "**/Private/**", "!**/Private/Share/**" ]
+	FilterRules []string `json:"FilterRules,omitempty"`
+
+	// DetectByContent detects the format of the objects using their content instead of their extension, so
+	// objects with wrong or missing extensions are uploaded too. The patterns are checked using the extension of
+	// the detected format. Objects that are not supported photos or videos are skipped. Already uploaded objects
+	// are not read.
+	DetectByContent bool `json:"DetectByContent,omitempty"`
+
+	// FileConditions sets the size, age and capture date of the objects to upload, besides the patterns.
+	// If it is not set, all the objects matching the patterns are uploaded.
+	FileConditions *FileConditions `json:"FileConditions,omitempty"`
+
+	// CaseInsensitivePatterns makes the IncludePatterns, ExcludePatterns, FilterRules, AlbumRules and .gphotosignore patterns
+	// match case-insensitively, so "**/*.jpg" matches "IMG_0001.JPG" too. Tagged patterns, like _IMAGE_EXTENSIONS_,
+	// always match case-insensitively.
+	CaseInsensitivePatterns bool `json:"CaseInsensitivePatterns,omitempty"`
+
+	// RawJpegPairing sets what to do with the RAW and JPEG objects of the same photo, like "IMG_1234.CR3" and
+	// "IMG_1234.JPG". Objects are paired when they are in the same folder and their names only differ in the
+	// extension. Skipped objects are tracked as paired, so they are not checked again.
+	//
+	// These are the valid values: "upload-both", "prefer-jpeg", "prefer-raw".
+	//   "upload-both": Uploads both objects. It's the default value.
+	//   "prefer-jpeg": Uploads only the JPEG object.
+	//   "prefer-raw": Uploads only the RAW object.
+	RawJpegPairing string `json:"RawJpegPairing,omitempty"`
+
+	// LivePhotoPairing sets what to do with the video of a Live Photo, like "IMG_1234.MOV" next to "IMG_1234.HEIC"
+	// or "IMG_1234.JPG". Objects are paired like in the RawJpegPairing option.
+	//
+	// These are the valid values: "upload-both", "skip-video".
+	//   "upload-both": Uploads the photo and the video. It's the default value.
+	//   "skip-video": Uploads only the photo.
+	LivePhotoPairing string `json:"LivePhotoPairing,omitempty"`
+}
+
+// AlbumRule sets the album of the objects that match all its conditions. At least one condition must be set.
+type AlbumRule struct {
+	// Pattern matches the path of the object relative to SourceFolder, like the IncludePatterns option.
+	//
+	//   Example: "**/Screenshot*"
+	Pattern string `json:"Pattern,omitempty"`
+
+	// Extensions are the extensions of the object, without the dot. They are case-insensitive.
+	//
+	//   Example: ["mp4", "mov"]
+	Extensions []string `json:"Extensions,omitempty"`
+
+	// MinSize and MaxSize are the limits of the object size, like "500KB" or "1.5GB".
+	MinSize string `json:"MinSize,omitempty"`
+	MaxSize string `json:"MaxSize,omitempty"`
+
+	// CameraModel is a pattern of the camera model read from the EXIF metadata. It's case-insensitive.
+	//
+	//   Example: "Pixel*"
+	CameraModel string `json:"CameraModel,omitempty"`
+
+	// Album is the album of the matching objects, using the same values as the Album option.
+	Album string `json:"Album"`
+}
+
+// FileConditions sets the conditions that objects must meet to be uploaded. Empty values are not checked.
+type FileConditions struct {
+	// MinSize and MaxSize are the limits of the object size, like "20KB" or "2GB". Units are powers of 1024.
+	MinSize string `json:"MinSize,omitempty"`
+	MaxSize string `json:"MaxSize,omitempty"`
+
+	// NewerThan and OlderThan are the limits of the age of the objects, using their modification time, like "30d",
+	// "2w" or "36h".
+	NewerThan string `json:"NewerThan,omitempty"`
+	OlderThan string `json:"OlderThan,omitempty"`
+
+	// CapturedFrom and CapturedTo are the first and the last days of the capture date of the objects, like
+	// "2024-05-31". The capture date is calculated using the DateSources option.
+	CapturedFrom string `json:"CapturedFrom,omitempty"`
+	CapturedTo   string `json:"CapturedTo,omitempty"`
+}
+
+// AlbumTitles sets how album titles are sanitized.
+type AlbumTitles struct {
+	// Normalize trims the titles, collapses consecutive whitespaces and removes control characters.
+	// Existing albums are found by title, so titles that change get new albums.
+	//
+	//   Default: false
+	Normalize bool `json:"Normalize,omitempty"`
+
+	// MaxLength is the maximum number of characters of the titles. Longer titles are truncated, ending with "…".
+	//
+	//   Default: 500
+	MaxLength int `json:"MaxLength,omitempty"`
+
+	// Replacements replaces strings in the titles, like { "_": " " }. Longer strings are replaced first.
+	Replacements map[string]string `json:"Replacements,omitempty"`
+
+	// DefaultAlbum is the album used when the title of the album of an object is empty after sanitizing it.
+	// If it is not set, those objects are not added to any album.
+	//
+	//   Example: "Unsorted"
+	DefaultAlbum string `json:"DefaultAlbum,omitempty"`
+}
+
+// AlbumEnrichments represents the files used to add enrichments to newly created albums.
+// Files are read from the folder of the objects uploaded to the album. Missing files are ignored.
+type AlbumEnrichments struct {
+	// NoteFile is the name of a text file whose content is added as a text enrichment.
+	//
+	//   Example: "README.txt"
+	NoteFile string `json:"NoteFile,omitempty"`
+
+	// LocationFile is the name of an HJSON file whose location is added as a location enrichment.
+	//
+	//   Example: "location.hjson", containing: { Name: "Lisbon", Latitude: 38.7223, Longitude: -9.1393 }
+	LocationFile string `json:"LocationFile,omitempty"`
 }
@@ -0,0 +1,58 @@
+// Package albumcounter keeps the number of media items of the albums, so they are known even when the Google Photos
+// API returns an outdated number.
+package albumcounter
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// LevelDBStore keeps the number of media items of the albums, indexed by album ID, using LevelDB.
+type LevelDBStore struct {
+	db   *leveldb.DB
+	path string
+}
+
+// NewStore creates a new LevelDBStore at the given path.
+func NewStore(path string) (*LevelDBStore, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LevelDBStore{
+		db:   db,
+		path: path,
+	}, nil
+}
+
+// Get returns the number of media items of the album, and false if it's not stored.
+func (s *LevelDBStore) Get(albumID string) (int64, bool) {
+	v, err := s.db.Get([]byte(albumID), nil)
+	if err != nil {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(string(v), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// Set stores the number of media items of the album.
+func (s *LevelDBStore) Set(albumID string, n int64) error {
+	return s.db.Put([]byte(albumID), []byte(strconv.FormatInt(n, 10)), nil)
+}
+
+// Close closes the store.
+func (s *LevelDBStore) Close() error {
+	return s.db.Close()
+}
+
+// Destroy completely remove an existing LevelDB database directory.
+func (s *LevelDBStore) Destroy() error {
+	_ = s.db.Close()
+	return os.RemoveAll(s.path)
+}
@@ -0,0 +1,36 @@
+package albumcounter_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/datastore/albumcounter"
+)
+
+func TestLevelDBStore_GetSet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "album_counts")
+
+	store, err := albumcounter.NewStore(path)
+	require.NoError(t, err)
+
+	_, found := store.Get("album-1")
+	assert.False(t, found)
+
+	require.NoError(t, store.Set("album-1", 42))
+	got, found := store.Get("album-1")
+	assert.True(t, found)
+	assert.Equal(t, int64(42), got)
+
+	// Counts are kept between executions.
+	require.NoError(t, store.Close())
+	store, err = albumcounter.NewStore(path)
+	require.NoError(t, err)
+	defer store.Destroy() //nolint:errcheck
+
+	got, found = store.Get("album-1")
+	assert.True(t, found)
+	assert.Equal(t, int64(42), got)
+}
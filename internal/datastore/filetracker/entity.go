@@ -10,16 +10,24 @@ import (
 type TrackedFile struct {
 	ModTime time.Time
 	Hash    string
+	// PairedWith is the sibling uploaded instead of the file by a pairing policy, like the JPEG file of a RAW
+	// file. It's empty if the file was uploaded.
+	PairedWith string
+	// MediaItemID is the media item of an uploaded file that was not added to all its extra albums yet. It's empty
+	// once the file has been added to all its albums.
+	MediaItemID string
 }
 
 // NewTrackedFile returns a TrackedFile with the specified values
 func NewTrackedFile(value string) TrackedFile {
-	parts := strings.SplitN(value, "|", 2)
+	parts := strings.SplitN(value, "|", 3)
 
 	modTime := time.Time{}
 	hash := ""
+	pairedWith := ""
+	mediaItemID := ""
 
-	if len(parts) == 2 {
+	if len(parts) >= 2 {
 		unixTime, err := strconv.ParseInt(parts[0], 10, 64)
 		if err == nil {
 			modTime = time.Unix(0, unixTime)
@@ -28,17 +36,37 @@ func NewTrackedFile(value string) TrackedFile {
 	} else {
 		hash = parts[0]
 	}
+	// Paired files are never pending, so the media item is stored after an empty paired file.
+	if len(parts) == 3 {
+		if id, found := strings.CutPrefix(parts[2], "|"); found {
+			mediaItemID = id
+		} else {
+			pairedWith = parts[2]
+		}
+	}
 
 	return TrackedFile{
-		Hash:    hash,
-		ModTime: modTime,
+		Hash:        hash,
+		ModTime:     modTime,
+		PairedWith:  pairedWith,
+		MediaItemID: mediaItemID,
 	}
 }
 
 func (tf TrackedFile) String() string {
-	if tf.ModTime.IsZero() {
+	var modTime string
+	if !tf.ModTime.IsZero() {
+		modTime = strconv.FormatInt(tf.ModTime.UnixNano(), 10)
+	}
+
+	switch {
+	case tf.MediaItemID != "":
+		return modTime + "|" + tf.Hash + "||" + tf.MediaItemID
+	case tf.PairedWith != "":
+		return modTime + "|" + tf.Hash + "|" + tf.PairedWith
+	case modTime == "":
 		return tf.Hash
-	} else {
-		return strconv.FormatInt(tf.ModTime.UnixNano(), 10) + "|" + tf.Hash
+	default:
+		return modTime + "|" + tf.Hash
 	}
 }
@@ -48,6 +48,51 @@ func TestTrackedFile_ModTime(t *testing.T) {
 	}
 }
 
+func TestTrackedFile_PairedWith(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"Should return empty value", "1631350013816466000|123456789", ""},
+		{"Should return the paired file", "1631350013816466000|123456789|/photos/IMG_1234.JPG", "/photos/IMG_1234.JPG"},
+		{"Should return the paired file containing separators", "1631350013816466000|123456789|/photos/IMG_1|2.JPG", "/photos/IMG_1|2.JPG"},
+		{"Should return empty value if there is a media item", "1631350013816466000|123456789||media-item-id", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := filetracker.NewTrackedFile(tc.input)
+			got := f.PairedWith
+			if tc.want != got {
+				t.Errorf("want: %s, got: %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestTrackedFile_MediaItemID(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"Should return empty value", "1631350013816466000|123456789", ""},
+		{"Should return empty value if it is paired", "1631350013816466000|123456789|/photos/IMG_1234.JPG", ""},
+		{"Should return the media item", "1631350013816466000|123456789||media-item-id", "media-item-id"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := filetracker.NewTrackedFile(tc.input)
+			got := f.MediaItemID
+			if tc.want != got {
+				t.Errorf("want: %s, got: %s", tc.want, got)
+			}
+		})
+	}
+}
+
 func TestTrackedFile_String(t *testing.T) {
 	testCases := []struct {
 		name  string
@@ -56,6 +101,8 @@ func TestTrackedFile_String(t *testing.T) {
 	}{
 		{"Should return the hash", "123456789", "123456789"},
 		{"Should return mtime and hash", "1631350013816466000|123456789", "1631350013816466000|123456789"},
+		{"Should return mtime, hash and paired file", "1631350013816466000|123456789|/photos/IMG_1|2.JPG", "1631350013816466000|123456789|/photos/IMG_1|2.JPG"},
+		{"Should return mtime, hash and media item", "1631350013816466000|123456789||media-item-id", "1631350013816466000|123456789||media-item-id"},
 	}
 
 	for _, tc := range testCases {
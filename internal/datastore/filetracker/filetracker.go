@@ -43,6 +43,23 @@ func New(r FileRepository) *FileTracker {
 
 // MarkAsUploaded marks a file as already uploaded.
 func (ft FileTracker) MarkAsUploaded(file string) error {
+	return ft.mark(file, TrackedFile{})
+}
+
+// MarkAsPaired marks a file as skipped by a pairing policy, because the sibling was uploaded instead.
+// Paired files are considered already uploaded, until they are modified.
+func (ft FileTracker) MarkAsPaired(file string, sibling string) error {
+	return ft.mark(file, TrackedFile{PairedWith: sibling})
+}
+
+// MarkAsPendingAlbums marks a file as uploaded, but not added to all its extra albums yet. The media item is kept,
+// so the file can be added to them without uploading it again.
+func (ft FileTracker) MarkAsPendingAlbums(file string, mediaItemID string) error {
+	return ft.mark(file, TrackedFile{MediaItemID: mediaItemID})
+}
+
+// mark stores the file with its current modification time and hash.
+func (ft FileTracker) mark(file string, item TrackedFile) error {
 	fileInfo, err := os.Stat(file)
 	if err != nil {
 		return err
@@ -52,10 +69,8 @@ func (ft FileTracker) MarkAsUploaded(file string) error {
 	if err != nil {
 		return err
 	}
-	item := TrackedFile{
-		ModTime: fileInfo.ModTime(),
-		Hash:    hash,
-	}
+	item.ModTime = fileInfo.ModTime()
+	item.Hash = hash
 
 	return ft.repo.Put(file, item)
 }
@@ -101,6 +116,26 @@ func (ft FileTracker) IsUploaded(file string) bool {
 	return false
 }
 
+// PendingMediaItem returns the media item of an uploaded file that was not added to all its extra albums yet.
+// It doesn't check if the file has been modified, see IsUploaded.
+func (ft FileTracker) PendingMediaItem(file string) (string, bool) {
+	item, found := ft.repo.Get(file)
+	if !found || item.MediaItemID == "" {
+		return "", false
+	}
+	return item.MediaItemID, true
+}
+
+// TrackedFile returns the modification time and hash stored for the file, if it's tracked.
+func (ft FileTracker) TrackedFile(file string) (TrackedFile, bool) {
+	return ft.repo.Get(file)
+}
+
+// Hash returns the current hash of the file, calculated like the stored ones.
+func (ft FileTracker) Hash(file string) (string, error) {
+	return ft.Hasher.Hash(file)
+}
+
 // UnmarkAsUploaded un-marks a file as already uploaded.
 func (ft FileTracker) UnmarkAsUploaded(file string) error {
 	return ft.repo.Delete(file)
@@ -39,6 +39,50 @@ func TestFileTracker_MarkAsUploaded(t *testing.T) {
 	}
 }
 
+func TestFileTracker_MarkAsPaired(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         string
+		isErrExpected bool
+	}{
+		{"Should success", ShouldSuccess, false},
+		{"Should fail if repo fails", ShouldMakeRepoFail, true},
+		{"Should fail if Hasher fails", ShouldMakeHashFail, true},
+	}
+
+	ft := filetracker.New(&mockedRepository{})
+	ft.Hasher = &mockedHasher{"test-file-hash"}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ft.MarkAsPaired(tc.input, "testdata/image.raw")
+			assertExpectedError(t, tc.isErrExpected, err)
+		})
+	}
+}
+
+func TestFileTracker_MarkAsPendingAlbums(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         string
+		isErrExpected bool
+	}{
+		{"Should success", ShouldSuccess, false},
+		{"Should fail if repo fails", ShouldMakeRepoFail, true},
+		{"Should fail if Hasher fails", ShouldMakeHashFail, true},
+	}
+
+	ft := filetracker.New(&mockedRepository{})
+	ft.Hasher = &mockedHasher{"test-file-hash"}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ft.MarkAsPendingAlbums(tc.input, "media-item-id")
+			assertExpectedError(t, tc.isErrExpected, err)
+		})
+	}
+}
+
 func TestFileTracker_IsUploaded(t *testing.T) {
 	testCases := []struct {
 		name  string
@@ -65,6 +109,64 @@ func TestFileTracker_IsUploaded(t *testing.T) {
 	}
 }
 
+func TestFileTracker_TrackedFile(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     string
+		want      filetracker.TrackedFile
+		wantFound bool
+	}{
+		{"Should return the tracked file", ShouldSuccess, filetracker.NewTrackedFile("1|test-file-hash"), true},
+		{"Should return false if file is not in the repo", ShouldMakeRepoFail, filetracker.TrackedFile{}, false},
+	}
+
+	ft := filetracker.New(&mockedRepository{
+		valueInRepo: filetracker.NewTrackedFile("1|test-file-hash"),
+	})
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, found := ft.TrackedFile(tc.input)
+			if tc.wantFound != found {
+				t.Errorf("want: %t, got: %t", tc.wantFound, found)
+			}
+			if tc.want != got {
+				t.Errorf("want: %v, got: %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFileTracker_PendingMediaItem(t *testing.T) {
+	testCases := []struct {
+		name      string
+		value     string
+		input     string
+		want      string
+		wantFound bool
+	}{
+		{"Should return the media item", "1|test-file-hash||media-item-id", ShouldSuccess, "media-item-id", true},
+		{"Should return false if file is not pending", "1|test-file-hash", ShouldSuccess, "", false},
+		{"Should return false if file is not in the repo", "1|test-file-hash||media-item-id", ShouldMakeRepoFail, "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ft := filetracker.New(&mockedRepository{
+				valueInRepo: filetracker.NewTrackedFile(tc.value),
+			})
+
+			got, found := ft.PendingMediaItem(tc.input)
+			if tc.wantFound != found {
+				t.Errorf("want: %t, got: %t", tc.wantFound, found)
+			}
+			if tc.want != got {
+				t.Errorf("want: %s, got: %s", tc.want, got)
+			}
+		})
+	}
+}
+
 func TestFileTracker_UnmarkAsUploaded(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -2,30 +2,79 @@ package filter
 
 import (
 	"fmt"
+	"strings"
 )
 
-// Filter is a file filter based on allowed and excluded patterns.
+// Filter is a file filter based on an ordered list of include and exclude rules. The last matching rule wins.
 type Filter struct {
-	allowedList  []string
-	excludedList []string
+	rules []rule
+}
+
+// rule includes or excludes the items matching any of its patterns.
+type rule struct {
+	include  bool
+	patterns []string
+	// foldedPatterns are lowercase patterns that match case-insensitively.
+	foldedPatterns []string
+}
+
+// Options sets how the patterns of a Filter match.
+type Options struct {
+	// CaseInsensitive makes all the patterns match case-insensitively. Built-in tagged patterns always match
+	// case-insensitively.
+	CaseInsensitive bool
+
+	// Tags are user-defined tagged patterns, like {"_PHONE_EXPORTS_": ["**/DCIM/**", "_HEIF_EXTENSIONS_"]}.
+	// They can reference other tags, and they can't replace the built-in ones (see ValidateTags).
+	Tags map[string][]string
 }
 
 // Compile returns an initialized Filter struct. If allowedList is empty, _IMAGE_EXTENSIONS_ tagged pattern is used instead.
 // It validates the patterns in allowedList and excludedList, returning error if they are not valid.
 func Compile(allowedList []string, excludedList []string) (*Filter, error) {
-	f := Filter{
-		allowedList:  translatePatternList(allowedList),
-		excludedList: translatePatternList(excludedList),
+	return CompileWithOptions(allowedList, excludedList, Options{})
+}
+
+// CompileWithOptions is like Compile but sets how the patterns match.
+// The patterns are translated into an include rule followed by an exclude rule, so excluded patterns win.
+func CompileWithOptions(allowedList []string, excludedList []string, options Options) (*Filter, error) {
+	include, err := newRule(true, allowedList, options)
+	if err != nil {
+		return nil, fmt.Errorf("include patterns are invalid: %w", err)
+	}
+	if len(include.patterns) == 0 && len(include.foldedPatterns) == 0 {
+		include = defaultIncludeRule()
 	}
 
-	if len(f.allowedList) == 0 {
-		f.allowedList = patternDictionary["_IMAGE_EXTENSIONS_"]
+	exclude, err := newRule(false, excludedList, options)
+	if err != nil {
+		return nil, fmt.Errorf("exclude patterns are invalid: %w", err)
 	}
 
-	if err := f.validate(); err != nil {
-		return nil, err
+	return &Filter{rules: []rule{include, exclude}}, nil
+}
+
+// CompileRules returns a Filter using an ordered list of rules. Rules exclude the items matching their pattern,
+// unless they start with '!', that includes them again. The last matching rule wins, and items that don't match
+// any rule are excluded. If the first rule is not an include rule, an include rule with the _IMAGE_EXTENSIONS_
+// tagged pattern is added before it.
+func CompileRules(rules []string, options Options) (*Filter, error) {
+	var f Filter
+	for _, value := range deleteEmpty(rules) {
+		pattern, include := strings.CutPrefix(value, "!")
+		if pattern == "" {
+			return nil, fmt.Errorf("filter rule '%s' is invalid: pattern is empty", value)
+		}
+		r, err := newRule(include, []string{pattern}, options)
+		if err != nil {
+			return nil, fmt.Errorf("filter rule '%s' is invalid: %w", value, err)
+		}
+		f.rules = append(f.rules, r)
 	}
 
+	if len(f.rules) == 0 || !f.rules[0].include {
+		f.rules = append([]rule{defaultIncludeRule()}, f.rules...)
+	}
 	return &f, nil
 }
 
@@ -40,30 +89,106 @@ func MustCompile(allowedList []string, excludedList []string) *Filter {
 }
 
 // IsAllowed returns if an item is allowed.
-// That means:
-//   - item is in the include pattern
-//   - item is not in the exclude pattern
+// That means the last rule matching the item is an include rule.
 func (f Filter) IsAllowed(fp string) bool {
-	// patterns has been validated before (see Compile), so no need to check error.
-	matched, _ := match(f.allowedList, fp)
-	return matched && !f.IsExcluded(fp)
+	for i := len(f.rules) - 1; i >= 0; i-- {
+		if f.rules[i].matches(fp) {
+			return f.rules[i].include
+		}
+	}
+	return false
+}
+
+// Explain returns the pattern of the last rule matching the item, and if the rule includes the item.
+// found is false if no rule matches the item, so it's not allowed.
+func (f Filter) Explain(fp string) (pattern string, include bool, found bool) {
+	for i := len(f.rules) - 1; i >= 0; i-- {
+		if pattern, found := f.rules[i].matchingPattern(fp); found {
+			return pattern, f.rules[i].include, true
+		}
+	}
+	return "", false, false
 }
 
 // IsExcluded return if an item should be excluded.
-// It's useful for skipping directories that match with an exclusion.
+// It's useful for skipping directories that match with an exclusion. A directory is not excluded when an include
+// rule follows the matching exclude rule, because it could include items inside the directory.
 func (f Filter) IsExcluded(fp string) bool {
-	// patterns has been validated before (see Compile), so no need to check error.
-	matched, _ := match(f.excludedList, fp)
-	return matched
+	for i := len(f.rules) - 1; i >= 0; i-- {
+		if !f.rules[i].matches(fp) {
+			continue
+		}
+		if f.rules[i].include {
+			return false
+		}
+		for _, r := range f.rules[i+1:] {
+			if r.include {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// newRule returns a rule once tagged patterns has been resolved, validating its patterns.
+func newRule(include bool, patternList []string, options Options) (rule, error) {
+	resolved, err := resolvePatternList(patternList, options)
+	if err != nil {
+		return rule{}, err
+	}
+	r := rule{include: include, patterns: resolved.patterns, foldedPatterns: resolved.foldedPatterns}
+	if err := r.validate(); err != nil {
+		return rule{}, err
+	}
+	return r, nil
+}
+
+// defaultIncludeRule returns the include rule used when no include patterns are set.
+func defaultIncludeRule() rule {
+	r, _ := newRule(true, []string{"_IMAGE_EXTENSIONS_"}, Options{})
+	return r
+}
+
+// matches returns true if the item matches any of the patterns of the rule.
+// Patterns has been validated before (see Compile), so no need to check error.
+func (r rule) matches(fp string) bool {
+	return matchAny(r.patterns, r.foldedPatterns, fp)
 }
 
-// validate returns error if allowedList or excludedList are not valid.
-func (f Filter) validate() error {
-	if err := validatePatternList(f.allowedList); err != nil {
-		return fmt.Errorf("include patterns are invalid: %w", err)
+// matchingPattern returns the first pattern of the rule matching the item.
+func (r rule) matchingPattern(fp string) (string, bool) {
+	for _, pattern := range r.patterns {
+		if matched, _ := match([]string{pattern}, fp); matched {
+			return pattern, true
+		}
 	}
-	if err := validatePatternList(f.excludedList); err != nil {
-		return fmt.Errorf("exclude patterns are invalid: %w", err)
+	lower := strings.ToLower(fp)
+	for _, pattern := range r.foldedPatterns {
+		if matched, _ := match([]string{pattern}, lower); matched {
+			return pattern, true
+		}
 	}
-	return nil
+	return "", false
+}
+
+// validate returns error if the patterns of the rule are not valid.
+func (r rule) validate() error {
+	if err := validatePatternList(r.patterns); err != nil {
+		return err
+	}
+	return validatePatternList(r.foldedPatterns)
+}
+
+// matchAny returns true if fp matches one of the patterns, or its lowercase form matches one of the folded patterns.
+// Patterns must have been validated before.
+func matchAny(patterns []string, foldedPatterns []string, fp string) bool {
+	if matched, _ := match(patterns, fp); matched {
+		return true
+	}
+	if len(foldedPatterns) == 0 {
+		return false
+	}
+	matched, _ := match(foldedPatterns, strings.ToLower(fp))
+	return matched
 }
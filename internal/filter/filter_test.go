@@ -355,3 +355,182 @@ func TestFilter_ExcludingAFolder(t *testing.T) {
 	})
 
 }
+
+func TestFilter_TaggedPatternsAreCaseInsensitive(t *testing.T) {
+	var testCases = []struct {
+		file string
+		out  bool
+	}{
+		{"testdata/SampleJPGImage.jpg", true},
+		{"testdata/SampleJPGImage.JPG", true},
+		{"testdata/SampleJPGImage.Jpg", true},
+		{"testdata/SampleRAWImage.Cr2", true},
+		{"testdata/SampleVideo.Mp4", false},
+	}
+
+	f, err := filter.Compile([]string{"_IMAGE_EXTENSIONS_", "_RAW_EXTENSIONS_"}, nil)
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.out, f.IsAllowed(tc.file), tc.file)
+	}
+}
+
+func TestFilter_CaseInsensitivePatterns(t *testing.T) {
+	var testCases = []struct {
+		file            string
+		caseSensitive   bool
+		caseInsensitive bool
+	}{
+		{"testdata/SamplePNGImage.png", true, true},
+		{"testdata/SamplePNGImage.PNG", false, true},
+		{"testdata/SamplePNGImage.Png", false, true},
+		{"testdata/ScreenShotPNG.png", true, false},
+		{"testdata/screenshots/SamplePNGImage.png", false, false},
+		{"testdata/Screenshots/SamplePNGImage.png", true, false},
+	}
+
+	allowed, excluded := []string{"**/*.png"}, []string{"**/screenshot*", "**/screenshots/*"}
+
+	t.Run("CaseSensitive", func(t *testing.T) {
+		f, err := filter.CompileWithOptions(allowed, excluded, filter.Options{})
+		require.NoError(t, err)
+
+		for _, tc := range testCases {
+			assert.Equal(t, tc.caseSensitive, f.IsAllowed(tc.file), tc.file)
+		}
+	})
+
+	t.Run("CaseInsensitive", func(t *testing.T) {
+		f, err := filter.CompileWithOptions(allowed, excluded, filter.Options{CaseInsensitive: true})
+		require.NoError(t, err)
+
+		for _, tc := range testCases {
+			assert.Equal(t, tc.caseInsensitive, f.IsAllowed(tc.file), tc.file)
+		}
+	})
+}
+
+func TestCompileRules(t *testing.T) {
+	testCases := []struct {
+		name        string
+		rules       []string
+		errExpected bool
+	}{
+		{name: "empty rules", rules: []string{""}, errExpected: false},
+		{name: "valid rules", rules: []string{"!**", "**/*.png"}, errExpected: false},
+		{name: "invalid rule", rules: []string{"[]a]"}, errExpected: true},
+		{name: "invalid include rule", rules: []string{"![]a]"}, errExpected: true},
+		{name: "empty include rule", rules: []string{"!"}, errExpected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := filter.CompileRules(tc.rules, filter.Options{})
+			if tc.errExpected {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFilter_Rules(t *testing.T) {
+	var testCases = []struct {
+		file     string
+		allowed  bool
+		excluded bool
+	}{
+		{"testdata/SampleJPGImage.jpg", true, false},
+		{"testdata/SampleText.txt", false, true},
+		{"testdata/Private/SampleJPGImage.jpg", false, false},
+		{"testdata/Private/Share/SampleJPGImage.jpg", true, false},
+		{"testdata/Private/Share/SampleText.txt", false, true},
+		{"testdata/Temp", false, true},
+	}
+
+	f, err := filter.CompileRules([]string{"**/Private/**", "!**/Private/Share/**", "**/*.txt", "**/Temp"}, filter.Options{})
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.allowed, f.IsAllowed(tc.file), "IsAllowed(%s)", tc.file)
+		assert.Equal(t, tc.excluded, f.IsExcluded(tc.file), "IsExcluded(%s)", tc.file)
+	}
+}
+
+func TestFilter_RulesStartingWithAnIncludeRule(t *testing.T) {
+	var testCases = []struct {
+		file string
+		out  bool
+	}{
+		{"testdata/SampleJPGImage.jpg", false},
+		{"testdata/SamplePNGImage.png", true},
+		{"testdata/SampleVideo.mp4", true},
+		{"testdata/Private/SamplePNGImage.png", false},
+	}
+
+	f, err := filter.CompileRules([]string{"!**/*.png", "!_ALL_VIDEO_FILES_", "**/Private/**"}, filter.Options{})
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.out, f.IsAllowed(tc.file), tc.file)
+	}
+}
+
+func TestFilter_Tags(t *testing.T) {
+	var testCases = []struct {
+		file string
+		out  bool
+	}{
+		{"testdata/IMG_0001.HEIC", true},
+		{"testdata/IMG_0001.avif", true},
+		{"testdata/IMG_0001.bmp", true},
+		{"testdata/DCIM/IMG_0001.mp4", true},
+		{"testdata/DCIM/IMG_0001.txt", false},
+		{"testdata/Exports/IMG_0001.txt", true},
+	}
+
+	tags := map[string][]string{
+		"_PHONE_EXPORTS_": {"_ALL_IMAGE_FILES_", "**/DCIM/**/*.mp4"},
+		"_ALL_EXPORTS_":   {"_PHONE_EXPORTS_", "**/Exports/**"},
+	}
+
+	f, err := filter.CompileWithOptions([]string{"_ALL_EXPORTS_"}, nil, filter.Options{Tags: tags})
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.out, f.IsAllowed(tc.file), tc.file)
+	}
+
+	t.Run("TagReferencingItself", func(t *testing.T) {
+		_, err := filter.CompileWithOptions([]string{"_LOOP_"}, nil, filter.Options{Tags: map[string][]string{"_LOOP_": {"_LOOP_"}}})
+		assert.Error(t, err)
+	})
+}
+
+func TestFilter_Explain(t *testing.T) {
+	var testCases = []struct {
+		file    string
+		pattern string
+		include bool
+		found   bool
+	}{
+		{file: "testdata/SampleJPGImage.JPG", pattern: "**/*.jpg", include: true, found: true},
+		{file: "testdata/Private/SampleJPGImage.jpg", pattern: "**/Private/**", include: false, found: true},
+		{file: "testdata/Private/Share/SampleJPGImage.jpg", pattern: "**/Private/Share/**", include: true, found: true},
+		{file: "testdata/SampleText.txt", found: false},
+	}
+
+	f, err := filter.CompileRules([]string{"**/Private/**", "!**/Private/Share/**"}, filter.Options{})
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		t.Run(tc.file, func(t *testing.T) {
+			pattern, include, found := f.Explain(tc.file)
+			assert.Equal(t, tc.pattern, pattern)
+			assert.Equal(t, tc.include, include)
+			assert.Equal(t, tc.found, found)
+		})
+	}
+}
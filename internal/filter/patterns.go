@@ -1,7 +1,16 @@
 package filter
 
-import "github.com/bmatcuk/doublestar/v2"
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
 
+	"github.com/bmatcuk/doublestar/v2"
+)
+
+// patternDictionary contains the built-in tagged patterns. They are lowercase, because they always match
+// case-insensitively. Tagged patterns can reference other tags.
 var patternDictionary = map[string][]string{
 	// _ALL_FILES match with all file extensions
 	"_ALL_FILES_": {"**"},
@@ -10,42 +19,132 @@ var patternDictionary = map[string][]string{
 	// Source: https://support.google.com/photos/answer/6193313
 	"_IMAGE_EXTENSIONS_": {
 		"**/*.jpg", "**/*.jpeg", "**/*.png", "**/*.webp", "**/*.gif",
-		"**/*.JPG", "**/*.JPEG", "**/*.PNG", "**/*.WEBP", "**/*.GIF",
 	},
 
+	// _HEIF_EXTENSIONS_ match with the HEIF file type extensions, used by Apple devices
+	"_HEIF_EXTENSIONS_": {"**/*.heic", "**/*.heif", "**/*.hif"},
+
+	// _AVIF_EXTENSIONS_ match with the AVIF file type extensions
+	"_AVIF_EXTENSIONS_": {"**/*.avif"},
+
+	// _BITMAP_EXTENSIONS_ match with the BMP, TIFF and ICO file type extensions
+	"_BITMAP_EXTENSIONS_": {"**/*.bmp", "**/*.tif", "**/*.tiff", "**/*.ico"},
+
+	// _ALL_IMAGE_FILES_ match with all the photos file type extensions supported by Google Photos, including the
+	// HEIF, AVIF and bitmap ones not included in _IMAGE_EXTENSIONS_
+	"_ALL_IMAGE_FILES_": {"_IMAGE_EXTENSIONS_", "_HEIF_EXTENSIONS_", "_AVIF_EXTENSIONS_", "_BITMAP_EXTENSIONS_"},
+
 	// _RAW_EXTENSIONS_ match with the RAW file type extensions
 	// Source: https://support.google.com/photos/answer/6193313
 	// Source: https://en.wikipedia.org/wiki/Raw_image_format#Raw_filename_extensions_and_respective_camera_manufacturers
 	"_RAW_EXTENSIONS_": {
 		"**/*.arw", "**/*.srf", "**/*.sr2", "**/*.crw", "**/*.cr2", "**/*.cr3", "**/*.dng", "**/*.nef", "**/*.nrw", "**/*.orf", "**/*.raf", "**/*.raw", "**/*.rw2",
-		"**/*.ARW", "**/*.SRF", "**/*.SR2", "**/*.CRW", "**/*.CR2", "**/*.CR3", "**/*.DNG", "**/*.NEF", "**/*.NRW", "**/*.ORF", "**/*.RAF", "**/*.RAW", "**/*.RW2",
 	},
 
 	// _ALL_VIDEO_FILES match with all video file extensions supported by Google Photos
 	// Source: https://support.google.com/photos/answer/6193313.
 	"_ALL_VIDEO_FILES_": {
 		"**/*.mpg", "**/*.mod", "**/*.mmv", "**/*.tod", "**/*.wmv", "**/*.asf", "**/*.avi", "**/*.divx", "**/*.mov", "**/*.m4v", "**/*.3gp", "**/*.3g2", "**/*.mp4", "**/*.m2t", "**/*.m2ts", "**/*.mts", "**/*.mkv",
-		"**/*.MPG", "**/*.MOD", "**/*.MMV", "**/*.TOD", "**/*.WMV", "**/*.ASF", "**/*.AVI", "**/*.DIVX", "**/*.MOV", "**/*.M4V", "**/*.3GP", "**/*.3G2", "**/*.MP4", "**/*.M2T", "**/*.M2TS", "**/*.MTS", "**/*.MKV",
 	},
+
+	// _ALL_MEDIA_FILES_ match with all the photos, RAW and video file type extensions supported by Google Photos
+	"_ALL_MEDIA_FILES_": {"_ALL_IMAGE_FILES_", "_RAW_EXTENSIONS_", "_ALL_VIDEO_FILES_"},
 }
 
-// translatePatternList returns an array of patterns once tagged patterns has been
-// resolved using patternDictionary.
-func translatePatternList(patternList []string) []string {
-	var r []string
+// tagNameRegexp matches the names of the tags, like _PHONE_EXPORTS_.
+var tagNameRegexp = regexp.MustCompile(`^_[A-Z0-9]+(_[A-Z0-9]+)*_$`)
+
+// resolvedPatterns are the patterns of a rule once tagged patterns have been resolved.
+type resolvedPatterns struct {
+	patterns []string
+	// foldedPatterns are lowercase patterns that match case-insensitively.
+	foldedPatterns []string
+}
+
+// add adds a pattern, lowercasing it if it matches case-insensitively.
+func (r *resolvedPatterns) add(pattern string, caseInsensitive bool) {
+	if caseInsensitive {
+		r.foldedPatterns = append(r.foldedPatterns, strings.ToLower(pattern))
+		return
+	}
+	r.patterns = append(r.patterns, pattern)
+}
+
+// resolvePatternList resolves the tagged patterns of patternList, using patternDictionary and the tags of the
+// options, and splits them into the patterns that match case-sensitively and the ones that match
+// case-insensitively. The latter include the patterns of the built-in tags and, if options.CaseInsensitive is true,
+// the rest of them.
+func resolvePatternList(patternList []string, options Options) (resolvedPatterns, error) {
+	var r resolvedPatterns
 	for _, p := range deleteEmpty(patternList) {
-		r = append(r, translatePattern(p)...)
+		if err := resolvePattern(p, options, nil, &r); err != nil {
+			return resolvedPatterns{}, err
+		}
 	}
-	return r
+	return r, nil
 }
 
-// translatePattern returns an array of patterns once a tagged pattern has been
-// resolved using patternDictionary
-func translatePattern(pattern string) []string {
-	if val, exist := patternDictionary[pattern]; exist {
-		return val
+// resolvePattern adds the pattern to r, resolving it if it's a tag. seen are the tags being resolved, to detect
+// tags that reference themselves.
+func resolvePattern(pattern string, options Options, seen []string, r *resolvedPatterns) error {
+	values, builtIn := patternDictionary[pattern]
+	if !builtIn {
+		var exist bool
+		if values, exist = options.Tags[pattern]; !exist {
+			r.add(pattern, options.CaseInsensitive)
+			return nil
+		}
+	}
+
+	if slices.Contains(seen, pattern) {
+		return fmt.Errorf("tag '%s' references itself", pattern)
 	}
-	return []string{pattern}
+	seen = append(seen, pattern)
+
+	for _, value := range deleteEmpty(values) {
+		if !isTag(value, options) {
+			r.add(value, builtIn || options.CaseInsensitive)
+			continue
+		}
+		if err := resolvePattern(value, options, seen, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isTag returns true if the pattern is a built-in or a user-defined tag.
+func isTag(pattern string, options Options) bool {
+	if _, builtIn := patternDictionary[pattern]; builtIn {
+		return true
+	}
+	_, exist := options.Tags[pattern]
+	return exist
+}
+
+// ValidateTags returns error if the user-defined tags are not valid. Their names must be like _NAME_, they can't
+// replace a built-in tag and their patterns must be valid.
+func ValidateTags(tags map[string][]string) error {
+	for name, values := range tags {
+		if !tagNameRegexp.MatchString(name) {
+			return fmt.Errorf("tag '%s' is invalid, it must be like _NAME_", name)
+		}
+		if _, builtIn := patternDictionary[name]; builtIn {
+			return fmt.Errorf("tag '%s' is a built-in tag", name)
+		}
+		if len(deleteEmpty(values)) == 0 {
+			return fmt.Errorf("tag '%s' has no patterns", name)
+		}
+
+		r, err := resolvePatternList([]string{name}, Options{Tags: tags})
+		if err != nil {
+			return err
+		}
+		if err := validatePatternList(append(r.patterns, r.foldedPatterns...)); err != nil {
+			return fmt.Errorf("tag '%s' is invalid: %w", name, err)
+		}
+	}
+	return nil
 }
 
 // deleteEmpty removes empty string from an array.
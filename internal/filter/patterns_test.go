@@ -1,8 +1,10 @@
 package filter
 
 import (
-	"github.com/stretchr/testify/assert"
+	"slices"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_DeleteEmpty(t *testing.T) {
@@ -75,3 +77,100 @@ func Test_Match(t *testing.T) {
 		})
 	}
 }
+
+func Test_ResolvePatternList(t *testing.T) {
+	tags := map[string][]string{
+		"_PHONE_EXPORTS_": {"**/DCIM/**", "_HEIF_EXTENSIONS_"},
+		"_ALL_EXPORTS_":   {"_PHONE_EXPORTS_", "**/Exports/*.JPG"},
+		"_LOOP_A_":        {"_LOOP_B_"},
+		"_LOOP_B_":        {"_LOOP_A_"},
+	}
+
+	testCases := []struct {
+		name        string
+		input       []string
+		options     Options
+		want        resolvedPatterns
+		errExpected bool
+	}{
+		{
+			name:  "built-in tag referencing other tags",
+			input: []string{"_ALL_MEDIA_FILES_"},
+			want: resolvedPatterns{foldedPatterns: slices.Concat(
+				patternDictionary["_IMAGE_EXTENSIONS_"],
+				patternDictionary["_HEIF_EXTENSIONS_"],
+				patternDictionary["_AVIF_EXTENSIONS_"],
+				patternDictionary["_BITMAP_EXTENSIONS_"],
+				patternDictionary["_RAW_EXTENSIONS_"],
+				patternDictionary["_ALL_VIDEO_FILES_"],
+			)},
+		},
+		{
+			name:    "user tag referencing other tags",
+			input:   []string{"_ALL_EXPORTS_", "**/*.png"},
+			options: Options{Tags: tags},
+			want: resolvedPatterns{
+				patterns:       []string{"**/DCIM/**", "**/Exports/*.JPG", "**/*.png"},
+				foldedPatterns: patternDictionary["_HEIF_EXTENSIONS_"],
+			},
+		},
+		{
+			name:    "case-insensitive user tag",
+			input:   []string{"_ALL_EXPORTS_"},
+			options: Options{Tags: tags, CaseInsensitive: true},
+			want: resolvedPatterns{
+				foldedPatterns: slices.Concat([]string{"**/dcim/**"}, patternDictionary["_HEIF_EXTENSIONS_"], []string{"**/exports/*.jpg"}),
+			},
+		},
+		{
+			name:  "unknown tag is a pattern",
+			input: []string{"_PHONE_EXPORTS_"},
+			want:  resolvedPatterns{patterns: []string{"_PHONE_EXPORTS_"}},
+		},
+		{
+			name:        "tag referencing itself",
+			input:       []string{"_LOOP_A_"},
+			options:     Options{Tags: tags},
+			errExpected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := resolvePatternList(tc.input, tc.options)
+			if tc.errExpected {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func Test_ValidateTags(t *testing.T) {
+	testCases := []struct {
+		name        string
+		tags        map[string][]string
+		errExpected bool
+	}{
+		{name: "valid tags", tags: map[string][]string{"_PHONE_EXPORTS_": {"**/DCIM/**"}, "_EXPORTS_": {"_PHONE_EXPORTS_"}}, errExpected: false},
+		{name: "invalid name", tags: map[string][]string{"PHONE": {"**/DCIM/**"}}, errExpected: true},
+		{name: "lowercase name", tags: map[string][]string{"_phone_": {"**/DCIM/**"}}, errExpected: true},
+		{name: "built-in tag", tags: map[string][]string{"_IMAGE_EXTENSIONS_": {"**/*.jpg"}}, errExpected: true},
+		{name: "empty tag", tags: map[string][]string{"_EMPTY_": {""}}, errExpected: true},
+		{name: "invalid pattern", tags: map[string][]string{"_BAD_": {"[]a]"}}, errExpected: true},
+		{name: "tag referencing itself", tags: map[string][]string{"_LOOP_": {"**/*.jpg", "_LOOP_"}}, errExpected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateTags(tc.tags)
+			if tc.errExpected {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
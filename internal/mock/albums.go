@@ -0,0 +1,47 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/albums"
+)
+
+// AlbumsService mocks the Google Photos albums service.
+type AlbumsService struct {
+	AddMediaItemsFn func(ctx context.Context, albumId string, mediaItemIds []string) error
+	CreateFn        func(ctx context.Context, title string) (*albums.Album, error)
+	GetByIdFn       func(ctx context.Context, id string) (*albums.Album, error)
+	GetByTitleFn    func(ctx context.Context, title string) (*albums.Album, error)
+	ListFn          func(ctx context.Context) ([]albums.Album, error)
+	PaginatedListFn func(ctx context.Context, options *albums.PaginatedListOptions) ([]albums.Album, string, error)
+}
+
+// AddMediaItems invokes the mock implementation.
+func (s *AlbumsService) AddMediaItems(ctx context.Context, albumId string, mediaItemIds []string) error {
+	return s.AddMediaItemsFn(ctx, albumId, mediaItemIds)
+}
+
+// Create invokes the mock implementation.
+func (s *AlbumsService) Create(ctx context.Context, title string) (*albums.Album, error) {
+	return s.CreateFn(ctx, title)
+}
+
+// GetById invokes the mock implementation.
+func (s *AlbumsService) GetById(ctx context.Context, id string) (*albums.Album, error) {
+	return s.GetByIdFn(ctx, id)
+}
+
+// GetByTitle invokes the mock implementation.
+func (s *AlbumsService) GetByTitle(ctx context.Context, title string) (*albums.Album, error) {
+	return s.GetByTitleFn(ctx, title)
+}
+
+// List invokes the mock implementation.
+func (s *AlbumsService) List(ctx context.Context) ([]albums.Album, error) {
+	return s.ListFn(ctx)
+}
+
+// PaginatedList invokes the mock implementation.
+func (s *AlbumsService) PaginatedList(ctx context.Context, options *albums.PaginatedListOptions) ([]albums.Album, string, error) {
+	return s.PaginatedListFn(ctx, options)
+}
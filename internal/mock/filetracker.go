@@ -5,6 +5,7 @@ type FileTracker struct {
 	MarkAsUploadedFn   func(path string) error
 	IsUploadedFn       func(path string) bool
 	UnmarkAsUploadedFn func(path string) error
+	PendingMediaItemFn func(path string) (string, bool)
 }
 
 // MarkAsUploaded invokes the mock implementation.
@@ -21,3 +22,11 @@ func (t *FileTracker) IsUploaded(path string) bool {
 func (t *FileTracker) UnmarkAsUploaded(path string) error {
 	return t.UnmarkAsUploadedFn(path)
 }
+
+// PendingMediaItem invokes the mock implementation, if it's set. Otherwise, files are not pending.
+func (t *FileTracker) PendingMediaItem(path string) (string, bool) {
+	if t.PendingMediaItemFn == nil {
+		return "", false
+	}
+	return t.PendingMediaItemFn(path)
+}
@@ -0,0 +1,219 @@
+package photos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gphotosuploader/google-photos-api-client-go/v3/albums"
+	"github.com/gphotosuploader/googlemirror/api/photoslibrary/v1"
+)
+
+const (
+	defaultBaseURL = "https://photoslibrary.googleapis.com/"
+
+	// MaxMediaItemsPerBatch is the maximum number of media items that can be added to (or removed from)
+	// an album in one call.
+	//
+	// See https://developers.google.com/photos/library/reference/rest/v1/albums/batchAddMediaItems.
+	MaxMediaItemsPerBatch = 50
+
+	// MaxAlbumItems is the maximum number of media items in an album.
+	//
+	// See https://developers.google.com/photos/library/guides/manage-albums.
+	MaxAlbumItems = 20000
+)
+
+// AlbumsService implements the albums calls to the Google Photos API that are not available in the
+// google-photos-api-client-go library. Only albums created by this app can be modified.
+// Enrichments are added using the photoslibrary client. The rest of the calls are not supported by it, so they are
+// sent by this service.
+type AlbumsService struct {
+	client  *http.Client
+	baseURL string
+
+	// library is the photoslibrary client. It's nil without an HTTP client.
+	library *photoslibrary.Service
+}
+
+// NewAlbumsService returns an AlbumsService using the given authenticated HTTP client.
+func NewAlbumsService(client *http.Client) *AlbumsService {
+	return NewAlbumsServiceWithBaseURL(client, defaultBaseURL)
+}
+
+// NewAlbumsServiceWithBaseURL returns an AlbumsService using a custom baseURL.
+// BaseURL should always be specified with a trailing slash.
+func NewAlbumsServiceWithBaseURL(client *http.Client, baseURL string) *AlbumsService {
+	// It only fails without an HTTP client.
+	library, _ := photoslibrary.New(client)
+	if library != nil {
+		library.BasePath = baseURL
+	}
+	return &AlbumsService{
+		client:  client,
+		baseURL: baseURL,
+		library: library,
+	}
+}
+
+// UpdateTitle changes the title of the album.
+func (s *AlbumsService) UpdateTitle(ctx context.Context, albumID string, title string) (*albums.Album, error) {
+	return s.patch(ctx, albumID, "title", map[string]string{"title": title})
+}
+
+// SetCoverPhoto sets the media item as cover photo of the album. The media item must be in the album.
+func (s *AlbumsService) SetCoverPhoto(ctx context.Context, albumID string, mediaItemID string) (*albums.Album, error) {
+	return s.patch(ctx, albumID, "coverPhotoMediaItemId", map[string]string{"coverPhotoMediaItemId": mediaItemID})
+}
+
+// RemoveMediaItems removes the media items from the album.
+// Only media items added to the album by this app can be removed.
+func (s *AlbumsService) RemoveMediaItems(ctx context.Context, albumID string, mediaItemIDs []string) error {
+	body := map[string][]string{"mediaItemIds": mediaItemIDs}
+	if err := s.do(ctx, http.MethodPost, "v1/albums/"+url.PathEscape(albumID)+":batchRemoveMediaItems", body, nil); err != nil {
+		return fmt.Errorf("removing media items from album: %w", err)
+	}
+	return nil
+}
+
+// Location is a geographic location used by location enrichments.
+type Location struct {
+	Name      string
+	Latitude  float64
+	Longitude float64
+}
+
+// AddTextEnrichment adds a text enrichment at the beginning of the album.
+func (s *AlbumsService) AddTextEnrichment(ctx context.Context, albumID string, text string) error {
+	item := &photoslibrary.NewEnrichmentItem{
+		TextEnrichment: &photoslibrary.TextEnrichment{Text: text},
+	}
+	return s.addEnrichment(ctx, albumID, item)
+}
+
+// AddLocationEnrichment adds a location enrichment at the beginning of the album.
+func (s *AlbumsService) AddLocationEnrichment(ctx context.Context, albumID string, location Location) error {
+	item := &photoslibrary.NewEnrichmentItem{
+		LocationEnrichment: &photoslibrary.LocationEnrichment{
+			Location: &photoslibrary.Location{
+				LocationName: location.Name,
+				Latlng: &photoslibrary.LatLng{
+					Latitude:  location.Latitude,
+					Longitude: location.Longitude,
+					// Zero is a valid coordinate, so it's sent too.
+					ForceSendFields: []string{"Latitude", "Longitude"},
+				},
+			},
+		},
+	}
+	return s.addEnrichment(ctx, albumID, item)
+}
+
+func (s *AlbumsService) addEnrichment(ctx context.Context, albumID string, item *photoslibrary.NewEnrichmentItem) error {
+	if s.library == nil {
+		return fmt.Errorf("adding enrichment to album: missing HTTP client")
+	}
+	req := &photoslibrary.AddEnrichmentToAlbumRequest{
+		NewEnrichmentItem: item,
+		AlbumPosition:     &photoslibrary.AlbumPosition{Position: "FIRST_IN_ALBUM"},
+	}
+	if _, err := s.library.Albums.AddEnrichment(albumID, req).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("adding enrichment to album: %w", err)
+	}
+	return nil
+}
+
+// album is the album resource returned by the API.
+type album struct {
+	ID                string `json:"id"`
+	Title             string `json:"title"`
+	ProductURL        string `json:"productUrl"`
+	IsWriteable       bool   `json:"isWriteable"`
+	MediaItemsCount   string `json:"mediaItemsCount"`
+	CoverPhotoBaseURL string `json:"coverPhotoBaseUrl"`
+}
+
+func (a album) toAlbum() *albums.Album {
+	count, _ := strconv.ParseInt(a.MediaItemsCount, 10, 64)
+	return &albums.Album{
+		ID:                a.ID,
+		Title:             a.Title,
+		ProductURL:        a.ProductURL,
+		IsWriteable:       a.IsWriteable,
+		TotalMediaItems:   count,
+		CoverPhotoBaseURL: a.CoverPhotoBaseURL,
+	}
+}
+
+// patch updates the album fields in the updateMask.
+func (s *AlbumsService) patch(ctx context.Context, albumID string, updateMask string, body interface{}) (*albums.Album, error) {
+	var res album
+	path := "v1/albums/" + url.PathEscape(albumID) + "?updateMask=" + url.QueryEscape(updateMask)
+	if err := s.do(ctx, http.MethodPatch, path, body, &res); err != nil {
+		return nil, fmt.Errorf("updating album: %w", err)
+	}
+	return res.toAlbum(), nil
+}
+
+// do sends a JSON request to the API and decodes the response into result, if it's not nil.
+func (s *AlbumsService) do(ctx context.Context, method string, path string, body interface{}, result interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close() //nolint:errcheck
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return newAPIError(res)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(result)
+}
+
+// APIError is an error returned by the Google Photos API.
+type APIError struct {
+	Code    int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("googleapi: error %d: %s", e.Code, e.Message)
+}
+
+func newAPIError(res *http.Response) error {
+	apiErr := &APIError{Code: res.StatusCode, Message: http.StatusText(res.StatusCode)}
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return apiErr
+	}
+
+	var payload struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(b, &payload) == nil && payload.Error.Message != "" {
+		apiErr.Message = payload.Error.Message
+	}
+	return apiErr
+}
@@ -0,0 +1,138 @@
+package photos_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/photos"
+)
+
+func TestAlbumsService_UpdateTitle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/v1/albums/album-id", r.URL.Path)
+		assert.Equal(t, "title", r.URL.Query().Get("updateMask"))
+		assert.JSONEq(t, `{"title":"New title"}`, readBody(t, r))
+
+		_, _ = io.WriteString(w, `{"id":"album-id","title":"New title","mediaItemsCount":"12","isWriteable":true}`)
+	}))
+	defer server.Close()
+
+	s := photos.NewAlbumsServiceWithBaseURL(server.Client(), server.URL+"/")
+	got, err := s.UpdateTitle(context.Background(), "album-id", "New title")
+
+	require.NoError(t, err)
+	assert.Equal(t, "album-id", got.ID)
+	assert.Equal(t, "New title", got.Title)
+	assert.Equal(t, int64(12), got.TotalMediaItems)
+	assert.True(t, got.IsWriteable)
+}
+
+func TestAlbumsService_SetCoverPhoto(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "coverPhotoMediaItemId", r.URL.Query().Get("updateMask"))
+		assert.JSONEq(t, `{"coverPhotoMediaItemId":"item-id"}`, readBody(t, r))
+
+		_, _ = io.WriteString(w, `{"id":"album-id","title":"Title"}`)
+	}))
+	defer server.Close()
+
+	s := photos.NewAlbumsServiceWithBaseURL(server.Client(), server.URL+"/")
+	got, err := s.SetCoverPhoto(context.Background(), "album-id", "item-id")
+
+	require.NoError(t, err)
+	assert.Equal(t, "album-id", got.ID)
+}
+
+func TestAlbumsService_RemoveMediaItems(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v1/albums/album-id:batchRemoveMediaItems", r.URL.Path)
+		assert.JSONEq(t, `{"mediaItemIds":["item-1","item-2"]}`, readBody(t, r))
+
+		_, _ = io.WriteString(w, `{}`)
+	}))
+	defer server.Close()
+
+	s := photos.NewAlbumsServiceWithBaseURL(server.Client(), server.URL+"/")
+	err := s.RemoveMediaItems(context.Background(), "album-id", []string{"item-1", "item-2"})
+
+	assert.NoError(t, err)
+}
+
+func TestAlbumsService_AddTextEnrichment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v1/albums/album-id:addEnrichment", r.URL.Path)
+		assert.JSONEq(t, `{"newEnrichmentItem":{"textEnrichment":{"text":"Our trip"}},"albumPosition":{"position":"FIRST_IN_ALBUM"}}`, readBody(t, r))
+
+		_, _ = io.WriteString(w, `{"enrichmentItem":{"id":"enrichment-id"}}`)
+	}))
+	defer server.Close()
+
+	s := photos.NewAlbumsServiceWithBaseURL(server.Client(), server.URL+"/")
+	err := s.AddTextEnrichment(context.Background(), "album-id", "Our trip")
+
+	assert.NoError(t, err)
+}
+
+func TestAlbumsService_AddLocationEnrichment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/albums/album-id:addEnrichment", r.URL.Path)
+		assert.JSONEq(t, `{"newEnrichmentItem":{"locationEnrichment":{"location":{"locationName":"Lisbon","latlng":{"latitude":38.72,"longitude":-9.14}}}},"albumPosition":{"position":"FIRST_IN_ALBUM"}}`, readBody(t, r))
+
+		_, _ = io.WriteString(w, `{"enrichmentItem":{"id":"enrichment-id"}}`)
+	}))
+	defer server.Close()
+
+	s := photos.NewAlbumsServiceWithBaseURL(server.Client(), server.URL+"/")
+	err := s.AddLocationEnrichment(context.Background(), "album-id", photos.Location{Name: "Lisbon", Latitude: 38.72, Longitude: -9.14})
+
+	assert.NoError(t, err)
+}
+
+func TestAlbumsService_AddLocationEnrichmentSendsZeroCoordinates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.JSONEq(t, `{"newEnrichmentItem":{"locationEnrichment":{"location":{"locationName":"Null Island","latlng":{"latitude":0,"longitude":0}}}},"albumPosition":{"position":"FIRST_IN_ALBUM"}}`, readBody(t, r))
+
+		_, _ = io.WriteString(w, `{"enrichmentItem":{"id":"enrichment-id"}}`)
+	}))
+	defer server.Close()
+
+	s := photos.NewAlbumsServiceWithBaseURL(server.Client(), server.URL+"/")
+	err := s.AddLocationEnrichment(context.Background(), "album-id", photos.Location{Name: "Null Island"})
+
+	assert.NoError(t, err)
+}
+
+func TestAlbumsService_ReturnsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": map[string]interface{}{"code": 403, "message": "No permission to update album."},
+		})
+	}))
+	defer server.Close()
+
+	s := photos.NewAlbumsServiceWithBaseURL(server.Client(), server.URL+"/")
+	_, err := s.UpdateTitle(context.Background(), "album-id", "New title")
+
+	var apiErr *photos.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusForbidden, apiErr.Code)
+	assert.Equal(t, "No permission to update album.", apiErr.Message)
+}
+
+func readBody(t *testing.T, r *http.Request) string {
+	b, err := io.ReadAll(r.Body)
+	require.NoError(t, err)
+	return string(b)
+}
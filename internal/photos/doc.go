@@ -0,0 +1,3 @@
+// Package photos implements Google Photos API calls that are not supported by the
+// google-photos-api-client-go library.
+package photos
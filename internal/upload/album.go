@@ -3,354 +3,211 @@ package upload
 import (
 	"fmt"
 	"path/filepath"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/log"
 )
 
-// albumName returns the album name based on the configured parameter.
-func (job *UploadFolderJob) albumName(filePath string, fileCreateTime time.Time) string {
-	before, after, found := strings.Cut(job.Album, ":")
-	if !found {
-		return ""
-	}
-	if before == "name" {
-		return after
+// templateData is the data of a file used to calculate its album names.
+type templateData struct {
+	// Path is the path of the file, relative to the SourceFolder.
+	Path        string
+	CaptureTime time.Time
+	CameraMake  string
+	CameraModel string
+	// Size is the size of the file in bytes.
+	Size int64
+	// EventStart and EventEnd are the times of the first and the last files of the event of the file, if any.
+	EventStart time.Time
+	EventEnd   time.Time
+	// Locale is the language of the month and weekday names.
+	Locale string
+}
+
+// templateData returns the data used to calculate the album names of the file.
+func (job *UploadFolderJob) templateData(filePath string, size int64, metadata fileMetadata) templateData {
+	return templateData{
+		Path:        filePath,
+		Size:        size,
+		CaptureTime: metadata.captureTime,
+		CameraMake:  metadata.cameraMake,
+		CameraModel: metadata.cameraModel,
+		Locale:      job.AlbumNameLocale,
 	}
+}
 
-	if before == "template" {
-		val, err := parseAlbumNameTemplate(after, filePath, fileCreateTime)
-		if err != nil {
-			panic("invalid Albums name template format - " + err.Error())
-		}
+// albumName returns the sanitized album name based on the nearest override file, the album rules or the configured
+// parameter. If the name is empty after sanitizing it, or it can't be calculated, the default album is used.
+func (job *UploadFolderJob) albumName(data templateData, logger log.Logger) string {
+	option := job.albumOption(data)
+	return job.albumNameOrDefault(option, data, logger)
+}
 
-		return val
+// albumNameOrDefault returns the sanitized album name based on an Album option value. The default album is used if
+// the name is empty after sanitizing it, or if it can't be calculated. The error is logged in that case.
+func (job *UploadFolderJob) albumNameOrDefault(option string, data templateData, logger log.Logger) string {
+	name, err := job.albumNameUsingOption(option, data)
+	if err != nil {
+		logger.Warnf("Using the default album for file '%s': %s", data.Path, err)
 	}
-
-	if before == "auto" {
-		switch after {
-		case "folderPath":
-			return albumNameUsingFolderPath(filePath)
-		case "folderName":
-			return albumNameUsingFolderName(filePath)
-		default:
-			panic("invalid Albums parameter")
-		}
+	name = job.albumTitle(name)
+	if name == "" && option != "" {
+		return job.albumTitle(job.AlbumTitles.DefaultAlbum)
 	}
-
-	return ""
+	return name
 }
 
-// albumNameUsingFolderPath returns an AlbumID name using the full Path of the given folder.
-func albumNameUsingFolderPath(path string) string {
-	p := filepath.Dir(path)
-	if p == "." {
-		return ""
+// albumNames returns the names of all the albums where the file is uploaded, based on the Album and Albums
+// configured parameters. The Album parameter is overridden by the nearest override file or the album rules, if any.
+// Names are sanitized, and empty and repeated names are removed, so the first one is the main album.
+func (job *UploadFolderJob) albumNames(data templateData, logger log.Logger) []string {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name == "" || seen[name] {
+			return
+		}
+		seen[name] = true
+		names = append(names, name)
 	}
 
-	p = strings.ReplaceAll(p, "/", "_")
-
-	// In path starts with '/' remove it before.
-	if p[0] == '_' {
-		return p[1:]
+	add(job.albumName(data, logger))
+	for _, option := range job.Albums {
+		add(job.albumNameOrDefault(option, data, logger))
 	}
-	return p
+	return names
 }
 
-// albumNameUsingFolderName returns an AlbumID name using the name of the given folder.
-func albumNameUsingFolderName(path string) string {
-	p := filepath.Dir(path)
-	if p == "." {
-		return ""
+// albumNameUsingOption returns the album name based on an Album option value. It returns an error if a template
+// fails with the data of the file, like a $pad function with a width that is not a number.
+func (job *UploadFolderJob) albumNameUsingOption(option string, data templateData) (string, error) {
+	before, after, found := strings.Cut(option, ":")
+	if !found {
+		return "", nil
+	}
+	if before == "name" {
+		return after, nil
 	}
-	return filepath.Base(p)
-}
-
-// ValidateAlbumNameTemplate validates the given template.
-func ValidateAlbumNameTemplate(template string) error {
-	_, err := parseAlbumNameTemplate(template, "", time.Now())
-	return err
-}
 
-// Recursively parse the template and replace the tokens with the corresponding values.
-func parseAlbumNameTemplate(template, filePath string, fileCreateTime time.Time) (string, error) {
-	outputs := ""
-	i := 0
-	for {
-		parserOutput, tokenLen, err := handleTokenParsing(template[i:], filePath, fileCreateTime)
+	if before == "template" {
+		t, err := job.albumTemplate(after)
 		if err != nil {
-			return "", err
-		}
-		if tokenLen > 0 {
-			outputs += parserOutput
-			i += tokenLen
+			panic("invalid Albums name template format - " + err.Error())
 		}
 
-		parserOutput, functionLen, err := handleFunctionParsing(template[i:], filePath, fileCreateTime)
+		val, err := t.execute(data)
 		if err != nil {
-			return "", err
-		}
-		if functionLen > 0 {
-			outputs += parserOutput
-			i += functionLen
-		}
-
-		if i == len(template) {
-			break
+			return "", fmt.Errorf("invalid album name template '%s': %w", after, err)
 		}
 
-		outputs += string(template[i])
-		i++
+		return val, nil
 	}
 
-	return outputs, nil
-}
-
-// Recursively parse the template and replace the functions with the corresponding values.
-// int result is the number of characters parsed
-func handleFunctionParsing(template string, filePath string, fileCreateTime time.Time) (string, int, error) {
-	functionName := getTemplateFunctionName(template)
-	if functionName == "" {
-		return "", 0, nil
-	}
-	i := len(functionName) + 2
-	functionDepth := 1
-	args := []string{}
-	currentArg := ""
-	for i < len(template) {
-		argInQuotes, quotesLenght, err := handleQuotesParsing(currentArg, template[i:], functionName)
-		if err != nil {
-			return "", 0, err
-		}
-
-		if quotesLenght > 0 {
-			i += quotesLenght
-			args = append(args, argInQuotes)
-		}
-
-		if template[i] == '(' {
-			functionDepth++
-		}
-
-		if template[i] == ')' {
-			functionDepth--
-		}
-
-		if (template[i] == ',' && functionDepth == 1) || functionDepth == 0 {
-			if quotesLenght == 0 {
-				val, err := parseAlbumNameTemplate(currentArg, filePath, fileCreateTime)
-				if err != nil {
-					return "", 0, err
-				}
-				args = append(args, val)
-			}
-
-			currentArg = ""
-		} else {
-			currentArg += string(template[i])
-		}
-
-		i++
-		if functionDepth == 0 {
-			break
+	if before == "auto" {
+		switch after {
+		case "folderPath":
+			return albumNameUsingFolderPath(data.Path), nil
+		case "folderName":
+			return albumNameUsingFolderName(data.Path), nil
+		case "event":
+			return job.albumNameUsingOption("template:"+job.eventAlbumName(), data)
+		default:
+			panic("invalid Albums parameter")
 		}
 	}
 
-	if functionDepth != 0 {
-		return "", 0, fmt.Errorf("function missing closing parenthesis")
-	}
-
-	val, err := runTemplateFunction(functionName, args)
-	if err != nil {
-		return "", 0, err
-	}
-
-	return val, i, err
-}
-
-func getTemplateFunctionName(template string) string {
-	// perf optimization to avoid regex if not needed
-	if (len(template) < 4) || (template[0] != '$') {
-		return ""
-	}
-
-	re := regexp.MustCompile(`^\$\b([a-zA-Z]+)\b\(`)
-	match := re.FindStringSubmatch(template)
-	if len(match) > 1 {
-		return match[1]
-	}
-	return ""
+	return "", nil
 }
 
-// Parse functon argument with quotes
-// int result is the number of characters parsed
-func handleQuotesParsing(preFix, template, functionName string) (string, int, error) {
-	if template[0] != '\'' {
-		return "", 0, nil
-	}
-
-	postFix := ""
-	output := ""
-	isOpen := true
-	length := 0
-	for i, v := range template[1:] {
-		length = i
-		if isOpen {
-			if v == '\'' {
-				isOpen = false
-				continue
+// compileAlbumTemplates compiles the templates of the Album, AlbumRules, Albums and EventAlbumName options, so they
+// are parsed only once per job.
+func (job *UploadFolderJob) compileAlbumTemplates() error {
+	for _, option := range append(job.albumOptions(), "template:"+job.eventAlbumName()) {
+		if template, found := strings.CutPrefix(option, "template:"); found {
+			if _, err := job.albumTemplate(template); err != nil {
+				return fmt.Errorf("invalid album name template '%s': %w", template, err)
 			}
-
-			output += string(v)
-		} else {
-			if v == ')' || v == ',' {
-				break
-			}
-			postFix += string(v)
 		}
 	}
+	return nil
+}
 
-	if isOpen {
-		return "", 0, fmt.Errorf("string missing closing quote")
+// albumOptions returns the values of the Album, AlbumRules and Albums options, and the ones set by the album override
+// files found so far.
+func (job *UploadFolderJob) albumOptions() []string {
+	options := append([]string{job.Album}, job.Albums...)
+	for _, rule := range job.AlbumRules {
+		options = append(options, rule.Album)
 	}
-
-	if strings.TrimSpace(postFix) != "" || strings.TrimSpace(preFix) != "" {
-		return "", 0, fmt.Errorf("can't mix quoted & unquoted content in function arg: %s", functionName)
+	for _, option := range job.albumOverrides {
+		options = append(options, option)
 	}
-
-	return output, length + 1, nil
+	return options
 }
 
-// Recursively parse the template and replace the tokens with the corresponding values.
-// int result is the number of characters parsed
-func handleTokenParsing(template string, filePath string, fileCreateTime time.Time) (string, int, error) {
-	tokenName := getTokenName(template)
-	if tokenName == "" {
-		return "", 0, nil
+// albumTemplate returns the compiled template, compiling it the first time it's used.
+func (job *UploadFolderJob) albumTemplate(template string) (*albumTemplate, error) {
+	if t, ok := job.albumTemplates[template]; ok {
+		return t, nil
 	}
 
-	tokenNameLen := len(tokenName) + 3
-	val, err := replaceTemplateToken(tokenName, filePath, fileCreateTime)
+	t, err := compileAlbumTemplate(template)
 	if err != nil {
-		return "", 0, err
+		return nil, err
 	}
-
-	return val, tokenNameLen, nil
+	if job.albumTemplates == nil {
+		job.albumTemplates = make(map[string]*albumTemplate)
+	}
+	job.albumTemplates[template] = t
+	return t, nil
 }
 
-func getTokenName(template string) string {
-	// perf optimization to avoid regex if not needed
-	if (len(template) < 4) || (template[0] != '%') || (template[1] != '_') {
+// mappedAlbumID returns the album ID set in the AlbumMap option for the given file, or an empty string if it's not mapped.
+// The nearest mapped folder of the file takes precedence over its album name.
+func (job *UploadFolderJob) mappedAlbumID(filePath string, albumName string) string {
+	if len(job.AlbumMap) == 0 {
 		return ""
 	}
-
-	re := regexp.MustCompile(`^%_([a-zA-Z_]+)%`)
-	match := re.FindStringSubmatch(template)
-	if len(match) > 1 {
-		return match[1]
+	for dir := filepath.Dir(filePath); dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+		if albumID, found := job.AlbumMap[filepath.ToSlash(dir)]; found {
+			return albumID
+		}
 	}
-	return ""
+	if albumName == "" {
+		return ""
+	}
+	return job.AlbumMap[albumName]
 }
 
-func runTemplateFunction(name string, args []string) (string, error) {
-	//empty first argument is 0 args
-	if len(args) == 1 && args[0] == "" {
-		args = []string{}
+// albumNameUsingFolderPath returns an AlbumID name using the full Path of the given folder.
+func albumNameUsingFolderPath(path string) string {
+	p := filepath.Dir(path)
+	if p == "." {
+		return ""
 	}
 
-	switch name {
-	case "cutLeft", "cutRight":
-		if len(args) != 2 {
-			return "", fmt.Errorf("%s requires 2 arguments", name)
-		}
-
-		cutN, err := strconv.Atoi(strings.TrimSpace(args[1]))
-
-		if err != nil {
-			return "", fmt.Errorf("%s requires a number as second argument", name)
-		}
-
-		if cutN >= len(args[0]) {
-			return "", nil
-		}
-
-		if name == "cutLeft" {
-			return args[0][cutN:], nil
-		}
-
-		if name == "cutRight" {
-			return args[0][:len(args[0])-cutN], nil
-		}
-	case "lower", "upper", "sentence", "title":
-		if len(args) != 1 {
-			return "", fmt.Errorf("%s requires 1 argument", name)
-		}
-
-		switch name {
-		case "lower":
-			return strings.ToLower(args[0]), nil
-		case "upper":
-			return strings.ToUpper(args[0]), nil
-		case "sentence":
-			runes := []rune(strings.ToLower(args[0]))
-			return strings.ToUpper(string(runes[0])) + string(runes[1:]), nil
-		case "title":
-			caser := cases.Title(language.English)
-			titleStr := caser.String(args[0])
-			return titleStr, nil
-		}
-	case "regexp":
-		if len(args) != 3 {
-			return "", fmt.Errorf("%s requires 3 arguments", name)
-		}
-
-		if args[1] == "" {
-			return args[0], nil
-		}
+	p = strings.ReplaceAll(p, "/", "_")
 
-		return regexpReplace(args[0], args[1], args[2])
-	default:
-		return "", fmt.Errorf("unknown function: %s", name)
+	// In path starts with '/' remove it before.
+	if p[0] == '_' {
+		return p[1:]
 	}
-
-	return "", nil
-}
-
-func regexpReplace(str, pattern, replace string) (result string, err error) {
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("invalid regexp pattern:%s", pattern)
-		}
-	}()
-
-	re := regexp.MustCompile(pattern)
-	result = re.ReplaceAllString(str, replace)
-	return
+	return p
 }
 
-func replaceTemplateToken(token, filePath string, fileCreateTime time.Time) (string, error) {
-	switch token {
-	case "folderpath":
-		return albumNameUsingFolderPath(filePath), nil
-	case "directory":
-		return albumNameUsingFolderName(filePath), nil
-	case "parent_directory":
-		return albumNameUsingFolderName(filepath.Dir(filePath)), nil
-	case "month":
-		return fileCreateTime.Format("01"), nil
-	case "day":
-		return fileCreateTime.Format("02"), nil
-	case "year":
-		return fileCreateTime.Format("2006"), nil
-	case "time":
-		return fileCreateTime.Format("15:04:05"), nil
-	case "time_en":
-		return fileCreateTime.Format("03:04:05 PM"), nil
+// albumNameUsingFolderName returns an AlbumID name using the name of the given folder.
+func albumNameUsingFolderName(path string) string {
+	p := filepath.Dir(path)
+	if p == "." {
+		return ""
 	}
+	return filepath.Base(p)
+}
 
-	return "", fmt.Errorf("invalid token: %s", token)
+// ValidateAlbumNameTemplate validates the given template. Errors include the position in the template where they
+// were found.
+func ValidateAlbumNameTemplate(template string) error {
+	_, err := parseAlbumNameTemplate(template, templateData{CaptureTime: time.Now()})
+	return err
 }
@@ -0,0 +1,71 @@
+package upload
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultAlbumNameLocale is the language of month and weekday names if it's not set.
+const DefaultAlbumNameLocale = "en"
+
+// localizedNames are the month and weekday names, indexed by locale.
+var localizedNames = map[string]struct {
+	months   [12]string
+	weekdays [7]string
+}{
+	"en": {
+		months:   [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		weekdays: [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	},
+	"es": {
+		months:   [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		weekdays: [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+	},
+	"fr": {
+		months:   [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		weekdays: [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+	},
+	"de": {
+		months:   [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		weekdays: [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+	},
+	"it": {
+		months:   [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		weekdays: [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+	},
+	"pt": {
+		months:   [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		weekdays: [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+	},
+	"nl": {
+		months:   [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		weekdays: [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+	},
+}
+
+// ValidateAlbumNameLocale validates that the locale is supported.
+func ValidateAlbumNameLocale(locale string) error {
+	if _, ok := localizedNames[locale]; !ok {
+		return fmt.Errorf("unsupported locale: %s", locale)
+	}
+	return nil
+}
+
+// localizedMonthName returns the name of the month in the given locale, or in the default one if it's not supported.
+func localizedMonthName(locale string, month time.Month) string {
+	names, ok := localizedNames[locale]
+	if !ok {
+		names = localizedNames[DefaultAlbumNameLocale]
+	}
+	return names.months[month-1]
+}
+
+// localizedWeekdayName returns the name of the weekday in the given locale, or in the default one if it's not
+// supported.
+func localizedWeekdayName(locale string, day time.Weekday) string {
+	names, ok := localizedNames[locale]
+	if !ok {
+		names = localizedNames[DefaultAlbumNameLocale]
+	}
+	return names.weekdays[day]
+}
@@ -0,0 +1,98 @@
+package upload
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AlbumOverrideFilename is the name of the file that overrides the Album option for the files of its folder and
+// subfolders. It contains an Album option value, like "name:Summer" or "template:%_year% - %_directory%".
+// A value without prefix is an album name.
+const AlbumOverrideFilename = ".gphotos-album"
+
+// readAlbumOverride returns the Album option value set by the override file of the folder, if any.
+func (job *UploadFolderJob) readAlbumOverride(dir string) (string, bool, error) {
+	b, err := os.ReadFile(filepath.Join(dir, AlbumOverrideFilename))
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	option, found := parseAlbumOverride(b)
+	if !found {
+		return "", false, nil
+	}
+	if err := job.validateAlbumOption(option); err != nil {
+		return "", false, err
+	}
+	return option, true, nil
+}
+
+// parseAlbumOverride returns the first line of the override file that is not empty or a comment, starting with '#'.
+func parseAlbumOverride(b []byte) (string, bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		before, _, found := strings.Cut(line, ":")
+		if !found || (before != "name" && before != "template" && before != "auto") {
+			return "name:" + line, true
+		}
+		return line, true
+	}
+	return "", false
+}
+
+// validateAlbumOption returns an error if the Album option value is not valid. Templates are compiled, so they are
+// ready to be used.
+func (job *UploadFolderJob) validateAlbumOption(option string) error {
+	before, after, _ := strings.Cut(option, ":")
+	switch before {
+	case "template":
+		if _, err := job.albumTemplate(after); err != nil {
+			return fmt.Errorf("invalid album name template '%s': %w", after, err)
+		}
+	case "auto":
+		if after != "folderPath" && after != "folderName" && after != "event" {
+			return fmt.Errorf("invalid album option '%s'", option)
+		}
+	}
+	return nil
+}
+
+// albumOption returns the Album option value for the file: the one set by the nearest override file, the first
+// matching album rule or the configured one.
+func (job *UploadFolderJob) albumOption(data templateData) string {
+	if option, found := job.albumOverride(data.Path); found {
+		return option
+	}
+	for _, rule := range job.AlbumRules {
+		if rule.matches(data) {
+			return rule.Album
+		}
+	}
+	return job.Album
+}
+
+// albumOverride returns the Album option value set by the nearest override file of the file, if any.
+func (job *UploadFolderJob) albumOverride(filePath string) (string, bool) {
+	if len(job.albumOverrides) == 0 {
+		return "", false
+	}
+	for dir := filepath.Dir(filePath); ; dir = filepath.Dir(dir) {
+		if option, found := job.albumOverrides[dir]; found {
+			return option, true
+		}
+		if dir == "." || dir == string(filepath.Separator) {
+			return "", false
+		}
+	}
+}
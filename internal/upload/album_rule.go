@@ -0,0 +1,58 @@
+package upload
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v2"
+)
+
+// AlbumRule sets the album of the files that match all its conditions. Conditions that are not set match all
+// the files.
+type AlbumRule struct {
+	// Filter matches the path of the file, relative to the SourceFolder.
+	Filter FileFilterer
+	// Extensions are the extensions of the file, without the dot. They are case-insensitive.
+	Extensions []string
+	// MinSize and MaxSize are the limits of the file size, in bytes. Zero is no limit.
+	MinSize int64
+	MaxSize int64
+	// CameraModel is a pattern of the camera model read from the EXIF metadata, like "Pixel*". It's case-insensitive.
+	CameraModel string
+
+	// Album is the Album option value used for the matching files.
+	Album string
+}
+
+// matches returns true if the file matches all the conditions of the rule.
+func (r AlbumRule) matches(data templateData) bool {
+	if r.Filter != nil && !r.Filter.IsAllowed(data.Path) {
+		return false
+	}
+	if len(r.Extensions) > 0 {
+		ext := strings.TrimPrefix(filepath.Ext(data.Path), ".")
+		if !slices.ContainsFunc(r.Extensions, func(e string) bool { return strings.EqualFold(strings.TrimPrefix(e, "."), ext) }) {
+			return false
+		}
+	}
+	if r.MinSize > 0 && data.Size < r.MinSize {
+		return false
+	}
+	if r.MaxSize > 0 && data.Size > r.MaxSize {
+		return false
+	}
+	if r.CameraModel != "" {
+		matched, _ := doublestar.Match(strings.ToLower(r.CameraModel), strings.ToLower(data.CameraModel))
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateCameraModelPattern validates the given camera model pattern.
+func ValidateCameraModelPattern(pattern string) error {
+	_, err := doublestar.Match(pattern, "x")
+	return err
+}
@@ -0,0 +1,69 @@
+package upload
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/filter"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/log"
+)
+
+func TestAlbumRule_Matches(t *testing.T) {
+	data := templateData{Path: "Camera/IMG_0001.JPG", Size: 2048, CameraModel: "Pixel 7"}
+
+	var testCases = []struct {
+		name string
+		rule AlbumRule
+		want bool
+	}{
+		{name: "WithoutConditions", rule: AlbumRule{}, want: true},
+		{name: "MatchingPattern", rule: AlbumRule{Filter: filter.MustCompile([]string{"Camera/**"}, nil)}, want: true},
+		{name: "NotMatchingPattern", rule: AlbumRule{Filter: filter.MustCompile([]string{"**/Screenshot*"}, nil)}, want: false},
+		{name: "MatchingExtension", rule: AlbumRule{Extensions: []string{"png", ".jpg"}}, want: true},
+		{name: "NotMatchingExtension", rule: AlbumRule{Extensions: []string{"mp4"}}, want: false},
+		{name: "MatchingSize", rule: AlbumRule{MinSize: 1024, MaxSize: 4096}, want: true},
+		{name: "TooSmall", rule: AlbumRule{MinSize: 4096}, want: false},
+		{name: "TooLarge", rule: AlbumRule{MaxSize: 1024}, want: false},
+		{name: "MatchingCameraModel", rule: AlbumRule{CameraModel: "pixel*"}, want: true},
+		{name: "NotMatchingCameraModel", rule: AlbumRule{CameraModel: "Canon*"}, want: false},
+		{name: "AllConditionsMustMatch", rule: AlbumRule{Extensions: []string{"jpg"}, CameraModel: "Canon*"}, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.rule.matches(data))
+		})
+	}
+}
+
+func TestUploadFolderJob_AlbumNameUsingRules(t *testing.T) {
+	job := UploadFolderJob{
+		Album: "template:%_year%",
+		AlbumRules: []AlbumRule{
+			{Filter: filter.MustCompile([]string{"**/Screenshot*"}, nil), Album: "name:Screenshots"},
+			{Filter: filter.MustCompile([]string{"WhatsApp/**"}, nil), Album: "name:WhatsApp"},
+			{Extensions: []string{"png"}, Album: "name:Images"},
+		},
+		albumOverrides: map[string]string{"Trips": "name:Trips"},
+	}
+	captureTime := time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+
+	var testCases = []struct {
+		path string
+		want string
+	}{
+		{path: "Phone/Screenshot_20240501.png", want: "Screenshots"},
+		{path: "WhatsApp/IMG-20240501-WA0001.jpg", want: "WhatsApp"},
+		{path: "Phone/drawing.png", want: "Images"},
+		{path: "Phone/IMG_0001.jpg", want: "2024"},
+		{path: "Trips/Screenshot_20240501.png", want: "Trips"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			assert.Equal(t, tc.want, job.albumName(templateData{Path: tc.path, CaptureTime: captureTime}, log.Discard))
+		})
+	}
+}
@@ -0,0 +1,625 @@
+package upload
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+var (
+	tokenRegexp    = regexp.MustCompile(`^%_([a-zA-Z0-9_]+)%`)
+	functionRegexp = regexp.MustCompile(`^\$\b([a-zA-Z]+)\b\(`)
+)
+
+// TemplateError is an error in an album name template.
+type TemplateError struct {
+	// Pos is the position of the error in the template, starting at 1.
+	Pos int
+	Msg string
+}
+
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("%s (at position %d)", e.Msg, e.Pos)
+}
+
+// albumTemplate is a compiled album name template. Templates are compiled once and then evaluated for every file.
+type albumTemplate struct {
+	nodes []templateNode
+}
+
+// templateNode is a node of the template tree: a text, a token or a function.
+type templateNode interface {
+	eval(data templateData) (string, error)
+}
+
+// textNode is a literal text.
+type textNode string
+
+func (n textNode) eval(templateData) (string, error) {
+	return string(n), nil
+}
+
+// tokenNode is a token, like %_year%.
+type tokenNode struct {
+	name  string
+	value func(data templateData) string
+}
+
+func (n tokenNode) eval(data templateData) (string, error) {
+	return n.value(data), nil
+}
+
+// functionNode is a function call, like $lower(...). Every argument is a list of nodes.
+type functionNode struct {
+	name string
+	pos  int
+	args [][]templateNode
+	fn   templateFunction
+	// re is the compiled regular expression of the regexp function, when the pattern is a literal text.
+	re *regexp.Regexp
+}
+
+func (n *functionNode) eval(data templateData) (string, error) {
+	args := make([]string, len(n.args))
+	for i, arg := range n.args {
+		v, err := evalNodes(arg, data)
+		if err != nil {
+			return "", err
+		}
+		args[i] = v
+	}
+
+	v, err := n.fn.run(n, args, data)
+	if err != nil {
+		return "", &TemplateError{Pos: n.pos, Msg: err.Error()}
+	}
+	return v, nil
+}
+
+func evalNodes(nodes []templateNode, data templateData) (string, error) {
+	var sb strings.Builder
+	for _, node := range nodes {
+		v, err := node.eval(data)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(v)
+	}
+	return sb.String(), nil
+}
+
+// execute returns the album name for the file.
+func (t *albumTemplate) execute(data templateData) (string, error) {
+	return evalNodes(t.nodes, data)
+}
+
+// usesCamera returns true if the template uses the camera of the files.
+func (t *albumTemplate) usesCamera() bool {
+	return usesNode(t.nodes, func(node templateNode) bool {
+		token, ok := node.(tokenNode)
+		return ok && (token.name == "camera_make" || token.name == "camera_model")
+	})
+}
+
+// usesCaptureTime returns true if the template uses the capture time of the files.
+func (t *albumTemplate) usesCaptureTime() bool {
+	return usesNode(t.nodes, func(node templateNode) bool {
+		switch n := node.(type) {
+		case tokenNode:
+			return captureTimeTokens[n.name]
+		case *functionNode:
+			return n.name == "date"
+		}
+		return false
+	})
+}
+
+// usesNode returns true if any node, or any node in the arguments of the functions, matches.
+func usesNode(nodes []templateNode, match func(node templateNode) bool) bool {
+	for _, node := range nodes {
+		if match(node) {
+			return true
+		}
+		if f, ok := node.(*functionNode); ok {
+			for _, arg := range f.args {
+				if usesNode(arg, match) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// compileAlbumTemplate parses the template and validates its tokens and functions.
+func compileAlbumTemplate(template string) (*albumTemplate, error) {
+	p := &templateParser{src: template}
+	nodes, err := p.parseNodes("")
+	if err != nil {
+		return nil, err
+	}
+	if err := validateNodes(nodes); err != nil {
+		return nil, err
+	}
+	return &albumTemplate{nodes: nodes}, nil
+}
+
+// parseAlbumNameTemplate compiles the template and evaluates it for the file.
+func parseAlbumNameTemplate(template string, data templateData) (string, error) {
+	t, err := compileAlbumTemplate(template)
+	if err != nil {
+		return "", err
+	}
+	return t.execute(data)
+}
+
+// templateParser builds the template tree.
+type templateParser struct {
+	src string
+	pos int
+}
+
+// parseNodes parses nodes until the end of the template or, inside an argument of the function, until the end of
+// the argument: a ',' or a ')' that is not closing a '(' of the argument.
+func (p *templateParser) parseNodes(function string) ([]templateNode, error) {
+	var nodes []templateNode
+	var text strings.Builder
+	depth := 0
+
+	flushText := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, textNode(text.String()))
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.src) {
+		rest := p.src[p.pos:]
+
+		if name := getTokenName(rest); name != "" {
+			flushText()
+			value, err := templateToken(name)
+			if err != nil {
+				return nil, &TemplateError{Pos: p.pos + 1, Msg: err.Error()}
+			}
+			nodes = append(nodes, tokenNode{name: name, value: value})
+			p.pos += len(name) + 3
+			continue
+		}
+
+		if name := getTemplateFunctionName(rest); name != "" {
+			flushText()
+			node, err := p.parseFunction(name)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+			continue
+		}
+
+		c := p.src[p.pos]
+		if function != "" {
+			switch {
+			case c == '(':
+				depth++
+			case c == ')' && depth > 0:
+				depth--
+			case c == ')' || c == ',':
+				flushText()
+				return nodes, nil
+			case c == '\'':
+				return nil, mixedArgError(p.pos, function)
+			}
+		}
+
+		text.WriteByte(c)
+		p.pos++
+	}
+
+	flushText()
+	return nodes, nil
+}
+
+// parseFunction parses a function call starting at the current position.
+func (p *templateParser) parseFunction(name string) (*functionNode, error) {
+	node := &functionNode{name: name, pos: p.pos + 1}
+	p.pos += len(name) + 2
+
+	for {
+		arg, err := p.parseArg(name)
+		if err != nil {
+			return nil, err
+		}
+		node.args = append(node.args, arg)
+
+		if p.pos >= len(p.src) {
+			return nil, &TemplateError{Pos: node.pos, Msg: "function missing closing parenthesis"}
+		}
+
+		c := p.src[p.pos]
+		p.pos++
+		if c == ')' {
+			break
+		}
+	}
+
+	// A function without arguments, like $lower(), has one empty argument.
+	if len(node.args) == 1 && node.args[0] == nil {
+		node.args = nil
+	}
+	return node, nil
+}
+
+// parseArg parses a function argument: a quoted text or a list of nodes.
+func (p *templateParser) parseArg(function string) ([]templateNode, error) {
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] == ' ' {
+		p.pos++
+	}
+	if p.pos >= len(p.src) || p.src[p.pos] != '\'' {
+		p.pos = start
+		return p.parseNodes(function)
+	}
+
+	quote := p.pos
+	end := strings.IndexByte(p.src[quote+1:], '\'')
+	if end < 0 {
+		return nil, &TemplateError{Pos: quote + 1, Msg: "string missing closing quote"}
+	}
+	text := p.src[quote+1 : quote+1+end]
+	p.pos = quote + end + 2
+
+	// Only spaces are allowed after the closing quote.
+	for p.pos < len(p.src) && p.src[p.pos] != ',' && p.src[p.pos] != ')' {
+		if p.src[p.pos] != ' ' {
+			return nil, mixedArgError(p.pos, function)
+		}
+		p.pos++
+	}
+	return []templateNode{textNode(text)}, nil
+}
+
+func mixedArgError(pos int, function string) error {
+	return &TemplateError{Pos: pos + 1, Msg: fmt.Sprintf("can't mix quoted & unquoted content in function arg: %s", function)}
+}
+
+// validateNodes checks that the functions exist and have valid arguments. Functions whose arguments are literal
+// texts are evaluated, so errors are found before the template is used.
+func validateNodes(nodes []templateNode) error {
+	for _, node := range nodes {
+		f, ok := node.(*functionNode)
+		if !ok {
+			continue
+		}
+
+		for _, arg := range f.args {
+			if err := validateNodes(arg); err != nil {
+				return err
+			}
+		}
+
+		fn, ok := templateFunctions[f.name]
+		if !ok {
+			return &TemplateError{Pos: f.pos, Msg: fmt.Sprintf("unknown function: %s", f.name)}
+		}
+		f.fn = fn
+
+		if len(f.args) < fn.minArgs || len(f.args) > fn.maxArgs {
+			return &TemplateError{Pos: f.pos, Msg: arityMessage(f.name, fn.minArgs, fn.maxArgs)}
+		}
+
+		if f.name == "regexp" {
+			if pattern, ok := literalText(f.args[1]); ok && pattern != "" {
+				re, err := regexp.Compile(pattern)
+				if err != nil {
+					return &TemplateError{Pos: f.pos, Msg: fmt.Sprintf("invalid regexp pattern:%s", pattern)}
+				}
+				f.re = re
+			}
+		}
+
+		if isLiteral(f.args) {
+			if _, err := f.eval(templateData{}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// literalText returns the text of an argument, if it's a literal text.
+func literalText(arg []templateNode) (string, bool) {
+	var sb strings.Builder
+	for _, node := range arg {
+		text, ok := node.(textNode)
+		if !ok {
+			return "", false
+		}
+		sb.WriteString(string(text))
+	}
+	return sb.String(), true
+}
+
+// isLiteral returns true if all the arguments are literal texts.
+func isLiteral(args [][]templateNode) bool {
+	for _, arg := range args {
+		if _, ok := literalText(arg); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func arityMessage(name string, minArgs int, maxArgs int) string {
+	switch {
+	case minArgs != maxArgs:
+		return fmt.Sprintf("%s requires %d or %d arguments", name, minArgs, maxArgs)
+	case minArgs == 1:
+		return fmt.Sprintf("%s requires 1 argument", name)
+	}
+	return fmt.Sprintf("%s requires %d arguments", name, minArgs)
+}
+
+func getTemplateFunctionName(template string) string {
+	// perf optimization to avoid regex if not needed
+	if (len(template) < 4) || (template[0] != '$') {
+		return ""
+	}
+
+	match := functionRegexp.FindStringSubmatch(template)
+	if len(match) > 1 {
+		return match[1]
+	}
+	return ""
+}
+
+func getTokenName(template string) string {
+	// perf optimization to avoid regex if not needed
+	if (len(template) < 4) || (template[0] != '%') || (template[1] != '_') {
+		return ""
+	}
+
+	match := tokenRegexp.FindStringSubmatch(template)
+	if len(match) > 1 {
+		return match[1]
+	}
+	return ""
+}
+
+// templateFunction is a function that can be used in templates.
+type templateFunction struct {
+	// minArgs and maxArgs are the number of arguments accepted by the function.
+	minArgs int
+	maxArgs int
+	run     func(f *functionNode, args []string, data templateData) (string, error)
+}
+
+var templateFunctions = map[string]templateFunction{
+	"cutLeft":  {minArgs: 2, maxArgs: 2, run: runCut},
+	"cutRight": {minArgs: 2, maxArgs: 2, run: runCut},
+	"lower": {minArgs: 1, maxArgs: 1, run: func(_ *functionNode, args []string, _ templateData) (string, error) {
+		return strings.ToLower(args[0]), nil
+	}},
+	"upper": {minArgs: 1, maxArgs: 1, run: func(_ *functionNode, args []string, _ templateData) (string, error) {
+		return strings.ToUpper(args[0]), nil
+	}},
+	"sentence": {minArgs: 1, maxArgs: 1, run: func(_ *functionNode, args []string, _ templateData) (string, error) {
+		runes := []rune(strings.ToLower(args[0]))
+		if len(runes) == 0 {
+			return "", nil
+		}
+		return strings.ToUpper(string(runes[0])) + string(runes[1:]), nil
+	}},
+	"title": {minArgs: 1, maxArgs: 1, run: func(_ *functionNode, args []string, _ templateData) (string, error) {
+		return cases.Title(language.English).String(args[0]), nil
+	}},
+	"regexp":  {minArgs: 3, maxArgs: 3, run: runRegexp},
+	"if":      {minArgs: 2, maxArgs: 3, run: runIf},
+	"ifempty": {minArgs: 2, maxArgs: 2, run: runIfEmpty},
+	"replace": {minArgs: 3, maxArgs: 3, run: runReplace},
+	"pad":     {minArgs: 2, maxArgs: 3, run: runPad},
+	"trim": {minArgs: 1, maxArgs: 1, run: func(_ *functionNode, args []string, _ templateData) (string, error) {
+		return strings.Join(strings.Fields(args[0]), " "), nil
+	}},
+	"date": {minArgs: 1, maxArgs: 1, run: func(_ *functionNode, args []string, data templateData) (string, error) {
+		return formatDate(args[0], data), nil
+	}},
+}
+
+func runCut(f *functionNode, args []string, _ templateData) (string, error) {
+	cutN, err := strconv.Atoi(strings.TrimSpace(args[1]))
+	if err != nil {
+		return "", fmt.Errorf("%s requires a number as second argument", f.name)
+	}
+
+	if cutN >= len(args[0]) {
+		return "", nil
+	}
+
+	if f.name == "cutLeft" {
+		return args[0][cutN:], nil
+	}
+	return args[0][:len(args[0])-cutN], nil
+}
+
+func runRegexp(f *functionNode, args []string, _ templateData) (string, error) {
+	if args[1] == "" {
+		return args[0], nil
+	}
+
+	re := f.re
+	if re == nil {
+		var err error
+		if re, err = regexp.Compile(args[1]); err != nil {
+			return "", fmt.Errorf("invalid regexp pattern:%s", args[1])
+		}
+	}
+	return re.ReplaceAllString(args[0], args[2]), nil
+}
+
+func runIf(_ *functionNode, args []string, _ templateData) (string, error) {
+	if strings.TrimSpace(args[0]) != "" {
+		return args[1], nil
+	}
+	if len(args) == 3 {
+		return args[2], nil
+	}
+	return "", nil
+}
+
+func runIfEmpty(_ *functionNode, args []string, _ templateData) (string, error) {
+	if strings.TrimSpace(args[0]) == "" {
+		return args[1], nil
+	}
+	return args[0], nil
+}
+
+func runReplace(_ *functionNode, args []string, _ templateData) (string, error) {
+	if args[1] == "" {
+		return args[0], nil
+	}
+	return strings.ReplaceAll(args[0], args[1], args[2]), nil
+}
+
+func runPad(f *functionNode, args []string, _ templateData) (string, error) {
+	width, err := strconv.Atoi(strings.TrimSpace(args[1]))
+	if err != nil {
+		return "", fmt.Errorf("%s requires a number as second argument", f.name)
+	}
+
+	padding := "0"
+	if len(args) == 3 {
+		padding = args[2]
+	}
+	if len([]rune(padding)) != 1 {
+		return "", fmt.Errorf("%s requires a single character as third argument", f.name)
+	}
+
+	if n := width - len([]rune(args[0])); n > 0 {
+		return strings.Repeat(padding, n) + args[0], nil
+	}
+	return args[0], nil
+}
+
+// formatDate formats the capture time of the file using the format. These are the valid placeholders:
+// YYYY (year), YY (two digits year), MMMM (month name), MM (month), DD (day), dddd (weekday name), HH (hour),
+// mm (minutes) and ss (seconds).
+func formatDate(format string, data templateData) string {
+	t := data.CaptureTime
+	r := strings.NewReplacer(
+		"YYYY", t.Format("2006"),
+		"YY", t.Format("06"),
+		"MMMM", localizedMonthName(data.Locale, t.Month()),
+		"MM", t.Format("01"),
+		"DD", t.Format("02"),
+		"dddd", localizedWeekdayName(data.Locale, t.Weekday()),
+		"HH", t.Format("15"),
+		"mm", t.Format("04"),
+		"ss", t.Format("05"),
+	)
+	return r.Replace(format)
+}
+
+// templateTokens are the values of the tokens, indexed by name.
+var templateTokens = map[string]func(data templateData) string{
+	"folderpath": func(data templateData) string { return albumNameUsingFolderPath(data.Path) },
+	"directory":  func(data templateData) string { return albumNameUsingFolderName(data.Path) },
+	"parent_directory": func(data templateData) string {
+		return albumNameUsingFolderName(filepath.Dir(data.Path))
+	},
+	"filename": func(data templateData) string {
+		name := filepath.Base(data.Path)
+		return strings.TrimSuffix(name, filepath.Ext(name))
+	},
+	"extension":    func(data templateData) string { return strings.TrimPrefix(filepath.Ext(data.Path), ".") },
+	"camera_make":  func(data templateData) string { return data.CameraMake },
+	"camera_model": func(data templateData) string { return data.CameraModel },
+	"month":        func(data templateData) string { return data.CaptureTime.Format("01") },
+	"month_name": func(data templateData) string {
+		return localizedMonthName(data.Locale, data.CaptureTime.Month())
+	},
+	"weekday": func(data templateData) string {
+		return localizedWeekdayName(data.Locale, data.CaptureTime.Weekday())
+	},
+	"week": func(data templateData) string {
+		_, week := data.CaptureTime.ISOWeek()
+		return fmt.Sprintf("%02d", week)
+	},
+	"week_year": func(data templateData) string {
+		year, _ := data.CaptureTime.ISOWeek()
+		return strconv.Itoa(year)
+	},
+	"quarter": func(data templateData) string {
+		return strconv.Itoa((int(data.CaptureTime.Month())-1)/3 + 1)
+	},
+	"day":     func(data templateData) string { return data.CaptureTime.Format("02") },
+	"year":    func(data templateData) string { return data.CaptureTime.Format("2006") },
+	"time":    func(data templateData) string { return data.CaptureTime.Format("15:04:05") },
+	"time_en": func(data templateData) string { return data.CaptureTime.Format("03:04:05 PM") },
+	"event_start": func(data templateData) string {
+		start, _ := eventDates(data)
+		return start.Format(time.DateOnly)
+	},
+	"event_end": func(data templateData) string {
+		_, end := eventDates(data)
+		return end.Format(time.DateOnly)
+	},
+	"event_dates": func(data templateData) string {
+		start, end := eventDates(data)
+		if start.Format(time.DateOnly) == end.Format(time.DateOnly) {
+			return start.Format(time.DateOnly)
+		}
+		return start.Format(time.DateOnly) + " - " + end.Format(time.DateOnly)
+	},
+}
+
+// captureTimeTokens are the tokens whose values are calculated using the capture time of the files.
+var captureTimeTokens = map[string]bool{
+	"month": true, "month_name": true, "weekday": true, "week": true, "week_year": true, "quarter": true, "day": true,
+	"year": true, "time": true, "time_en": true, "event_start": true, "event_end": true, "event_dates": true,
+}
+
+// eventDates returns the times of the first and the last files of the event. Files without an event are an event
+// by themselves.
+func eventDates(data templateData) (time.Time, time.Time) {
+	if data.EventStart.IsZero() {
+		return data.CaptureTime, data.CaptureTime
+	}
+	return data.EventStart, data.EventEnd
+}
+
+// templateToken returns the function that calculates the value of the token.
+func templateToken(name string) (func(data templateData) string, error) {
+	if value, ok := templateTokens[name]; ok {
+		return value, nil
+	}
+
+	if n, found := strings.CutPrefix(name, "segment_"); found {
+		i, err := strconv.Atoi(n)
+		if err == nil && i > 0 {
+			return func(data templateData) string { return pathSegment(data.Path, i) }, nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid token: %s", name)
+}
+
+// pathSegment returns the n-th folder of the path, starting at 1. It returns an empty string if the path doesn't
+// have so many folders.
+func pathSegment(path string, n int) string {
+	dir := filepath.Dir(path)
+	if dir == "." {
+		return ""
+	}
+	segments := strings.Split(filepath.ToSlash(strings.TrimPrefix(dir, string(filepath.Separator))), "/")
+	if n > len(segments) {
+		return ""
+	}
+	return segments[n-1]
+}
@@ -0,0 +1,64 @@
+package upload
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/mock"
+)
+
+func TestCompileAlbumTemplate(t *testing.T) {
+	data := templateData{
+		Path:        "Trips/Paris/IMG_0001.jpg",
+		CaptureTime: time.Date(2023, time.July, 14, 10, 0, 0, 0, time.UTC),
+	}
+
+	var testCases = []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{name: "Text", template: "Holidays", want: "Holidays"},
+		{name: "AdjacentTokens", template: "%_year%%_month%", want: "202307"},
+		{name: "TokenAfterFunction", template: "$upper(%_directory%)%_year%", want: "PARIS2023"},
+		{name: "ParenthesesInArgument", template: "$lower(Paris (France))", want: "paris (france)"},
+		{name: "NestedFunctions", template: "$upper($cutLeft(%_directory%, 2))", want: "RIS"},
+		{name: "RegexpWithTokenPattern", template: "$regexp(Paris,%_directory%,Rome)", want: "Rome"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl, err := compileAlbumTemplate(tc.template)
+			require.NoError(t, err)
+
+			got, err := tmpl.execute(data)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestUploadFolderJob_AlbumTemplate(t *testing.T) {
+	job := UploadFolderJob{}
+
+	first, err := job.albumTemplate("%_year%")
+	require.NoError(t, err)
+	second, err := job.albumTemplate("%_year%")
+	require.NoError(t, err)
+
+	assert.Same(t, first, second, "the template should be compiled only once")
+}
+
+func TestUploadFolderJob_ScanFolder_InvalidTemplate(t *testing.T) {
+	job := UploadFolderJob{
+		SourceFolder: t.TempDir(),
+		Album:        "template:$lower(%_ABC%)",
+	}
+
+	_, err := job.ScanFolder(&mock.Logger{})
+
+	assert.EqualError(t, err, "invalid album name template '$lower(%_ABC%)': invalid token: ABC (at position 8)")
+}
@@ -5,6 +5,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/log"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/mock"
 )
 
 func TestAlbumName(t *testing.T) {
@@ -53,7 +56,7 @@ func TestAlbumName(t *testing.T) {
 				Album: tt.album,
 			}
 
-			assert.Equal(t, tt.want, job.albumName(tt.in, timeObj))
+			assert.Equal(t, tt.want, job.albumName(templateData{Path: tt.in, CaptureTime: timeObj}, log.Discard))
 		})
 
 	}
@@ -68,7 +71,7 @@ func TestAlbumNameWithInvalidParameter(t *testing.T) {
 	job := UploadFolderJob{
 		Album: "auto:fooBar",
 	}
-	_ = job.albumName("/foo/bar/file.jpg", time.Now())
+	_ = job.albumName(templateData{Path: "/foo/bar/file.jpg", CaptureTime: time.Now()}, log.Discard)
 }
 
 func TestAlbumNameWithInvalidTemplate(t *testing.T) {
@@ -80,7 +83,90 @@ func TestAlbumNameWithInvalidTemplate(t *testing.T) {
 	job := UploadFolderJob{
 		Album: "template:%_ABC%",
 	}
-	_ = job.albumName("/foo/bar/file.jpg", time.Now())
+	_ = job.albumName(templateData{Path: "/foo/bar/file.jpg", CaptureTime: time.Now()}, log.Discard)
+}
+
+func TestAlbumNameWithFailingTemplate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		album        string
+		defaultAlbum string
+		want         string
+	}{
+		{name: "pad with a token width", album: "template:$pad(%_directory%,%_directory%)", defaultAlbum: "Unsorted", want: "Unsorted"},
+		{name: "regexp with a token pattern", album: "template:$regexp(Paris,%_directory%,Rome)", defaultAlbum: "Unsorted", want: "Unsorted"},
+		{name: "without default album", album: "template:$pad(%_directory%,%_directory%)", want: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := UploadFolderJob{
+				Album:       tc.album,
+				AlbumTitles: AlbumTitleOptions{DefaultAlbum: tc.defaultAlbum},
+			}
+			logger := &mock.Logger{}
+			assert.Equal(t, tc.want, job.albumName(templateData{Path: "(/file.jpg"}, logger))
+			assert.True(t, logger.WarnfInvoked)
+		})
+	}
+}
+
+func TestAlbumNames(t *testing.T) {
+	timeObj := time.Date(2034, time.December, 31, 16, 5, 59, 0, time.UTC)
+
+	var testData = []struct {
+		name   string
+		album  string
+		albums []string
+		want   []string
+	}{
+		{name: "without albums", album: "", albums: nil, want: nil},
+		{name: "only the Album option", album: "name:foo", albums: nil, want: []string{"foo"}},
+		{name: "Album and Albums options", album: "name:foo", albums: []string{"template:%_year%", "name:Family"}, want: []string{"foo", "2034", "Family"}},
+		{name: "only the Albums option", album: "", albums: []string{"template:%_directory%", "name:Family"}, want: []string{"bar", "Family"}},
+		{name: "repeated names are removed", album: "template:%_directory%", albums: []string{"name:bar", "name:Family"}, want: []string{"bar", "Family"}},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			job := UploadFolderJob{
+				Album:  tt.album,
+				Albums: tt.albums,
+			}
+			assert.Equal(t, tt.want, job.albumNames(templateData{Path: "/foo/bar/file.jpg", CaptureTime: timeObj}, log.Discard))
+		})
+	}
+}
+
+func TestMappedAlbumID(t *testing.T) {
+	job := UploadFolderJob{
+		AlbumMap: map[string]string{
+			"foo":         "id-foo",
+			"foo/bar":     "id-foo-bar",
+			"Summer Trip": "id-summer-trip",
+		},
+	}
+
+	var testData = []struct {
+		name      string
+		in        string
+		albumName string
+		want      string
+	}{
+		{name: "file in a mapped folder", in: "foo/file.jpg", albumName: "", want: "id-foo"},
+		{name: "nearest mapped folder wins", in: "foo/bar/file.jpg", albumName: "", want: "id-foo-bar"},
+		{name: "file in a subfolder of a mapped folder", in: "foo/baz/file.jpg", albumName: "", want: "id-foo"},
+		{name: "mapped folder wins over album name", in: "foo/file.jpg", albumName: "Summer Trip", want: "id-foo"},
+		{name: "mapped album name", in: "baz/file.jpg", albumName: "Summer Trip", want: "id-summer-trip"},
+		{name: "not mapped", in: "baz/file.jpg", albumName: "Winter Trip", want: ""},
+		{name: "file in the source folder", in: "file.jpg", albumName: "", want: ""},
+	}
+
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, job.mappedAlbumID(tt.in, tt.albumName))
+		})
+	}
 }
 
 func TestAlbumNameUsingFolderPath(t *testing.T) {
@@ -158,7 +244,44 @@ func TestParseAlbumNameTemplateWithTokens(t *testing.T) {
 	}
 
 	for _, tt := range testData {
-		val, err := parseAlbumNameTemplate(tt.in, filePath, timeObj)
+		val, err := parseAlbumNameTemplate(tt.in, templateData{Path: filePath, CaptureTime: timeObj})
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		assert.Equal(t, tt.out, val)
+	}
+}
+
+func TestParseAlbumNameTemplateWithFileTokens(t *testing.T) {
+	data := templateData{
+		Path:        "2021/Trips/Lisbon/IMG_0001.JPG",
+		CaptureTime: time.Date(2021, time.January, 3, 10, 15, 0, 0, time.UTC),
+		CameraMake:  "Canon",
+		CameraModel: "Canon EOS R5",
+	}
+
+	var testData = []struct {
+		in     string
+		locale string
+		out    string
+	}{
+		{in: "%_filename%", out: "IMG_0001"},
+		{in: "%_extension%", out: "JPG"},
+		{in: "%_camera_make%", out: "Canon"},
+		{in: "%_camera_model% / %_week_year%-W%_week%", out: "Canon EOS R5 / 2020-W53"},
+		{in: "%_year%-Q%_quarter%", out: "2021-Q1"},
+		{in: "%_month_name%, %_weekday%", out: "January, Sunday"},
+		{in: "%_month_name%, %_weekday%", locale: "es", out: "enero, domingo"},
+		{in: "%_month_name%, %_weekday%", locale: "de", out: "Januar, Sonntag"},
+		{in: "%_segment_1%", out: "2021"},
+		{in: "%_segment_3% %_segment_2%", out: "Lisbon Trips"},
+		{in: "%_segment_4%", out: ""},
+	}
+
+	for _, tt := range testData {
+		data.Locale = tt.locale
+		val, err := parseAlbumNameTemplate(tt.in, data)
 		if err != nil {
 			t.Errorf("Unexpected error: %v", err)
 		}
@@ -190,10 +313,25 @@ func TestParseAlbumNameTemplateWithFunctions(t *testing.T) {
 		{in: "$regexp(Hello _World!,_, ',')", out: "Hello ,World!"},
 		{in: "$regexp(Hello _World!,'[_!]', ',' )", out: "Hello ,World,"},
 		{in: "$regexp(Hello World!,, )", out: "Hello World!"},
+
+		{in: "$if(%_directory%,%_directory%,No folder)", out: "bar"},
+		{in: "$if(,yes,no)", out: "no"},
+		{in: "$if( ,yes)", out: ""},
+		{in: "$ifempty(%_directory%,Unsorted)", out: "bar"},
+		{in: "$ifempty(,Unsorted)", out: "Unsorted"},
+		{in: "$replace(Hello World!, World, Universe)", out: "Hello Universe!"},
+		{in: "$replace(a.b.c,'.','/')", out: "a/b/c"},
+		{in: "$pad(7,3)", out: "007"},
+		{in: "$pad(7, 3,'_')", out: "__7"},
+		{in: "$pad(1234,3)", out: "1234"},
+		{in: "$trim(  Hello   World!  )", out: "Hello World!"},
+		{in: "$date(YYYY-MM-DD HH:mm:ss)", out: "2034-12-31 16:05:59"},
+		{in: "$date(MMMM YY)", out: "December 34"},
+		{in: "$date(dddd)", out: "Sunday"},
 	}
 
 	for _, tt := range testData {
-		val, err := parseAlbumNameTemplate(tt.in, filePath, timeObj)
+		val, err := parseAlbumNameTemplate(tt.in, templateData{Path: filePath, CaptureTime: timeObj})
 		if err != nil {
 			t.Errorf("Unexpected error: %v", err)
 		}
@@ -210,24 +348,35 @@ func TestParseAlbumNameTemplateWithInvalidParameter(t *testing.T) {
 		in  string
 		err string
 	}{
-		{in: "%_ABC%", err: "invalid token: ABC"},
-		{in: "$ABC(Z)", err: "unknown function: ABC"},
-		{in: "$cutLeft(Z,Z)", err: "cutLeft requires a number as second argument"},
-		{in: "$cutLeft(Z,Z, Z)", err: "cutLeft requires 2 arguments"},
-		{in: "$cutLeft(Z)", err: "cutLeft requires 2 arguments"},
-		{in: "$cutLeft($cutLeft(Z)", err: "function missing closing parenthesis"},
-		{in: "$cutLeft($cutLeft(Z), 2)", err: "cutLeft requires 2 arguments"},
-		{in: "$cutLeft($cutRight(Z), 2)", err: "cutRight requires 2 arguments"},
-		{in: "$lower()", err: "lower requires 1 argument"},
-
-		{in: "$regexp(Hello World!, ^[a-z+\\[$, Universe)", err: "invalid regexp pattern: ^[a-z+\\[$"},
-		{in: "$regexp(Hello World!, _, ABC'()')", err: "can't mix quoted & unquoted content in function arg: regexp"},
-		{in: "$regexp(Hello World!, _, ')", err: "string missing closing quote"},
-		{in: "$regexp(Hello World!, _)", err: "regexp requires 3 arguments"},
+		{in: "%_ABC%", err: "invalid token: ABC (at position 1)"},
+		{in: "%_segment_0%", err: "invalid token: segment_0 (at position 1)"},
+		{in: "%_segment_x%", err: "invalid token: segment_x (at position 1)"},
+		{in: "$ABC(Z)", err: "unknown function: ABC (at position 1)"},
+		{in: "$cutLeft(Z,Z)", err: "cutLeft requires a number as second argument (at position 1)"},
+		{in: "$cutLeft(Z,Z, Z)", err: "cutLeft requires 2 arguments (at position 1)"},
+		{in: "$cutLeft(Z)", err: "cutLeft requires 2 arguments (at position 1)"},
+		{in: "$cutLeft($cutLeft(Z)", err: "function missing closing parenthesis (at position 1)"},
+		{in: "$cutLeft($cutLeft(Z), 2)", err: "cutLeft requires 2 arguments (at position 10)"},
+		{in: "$cutLeft($cutRight(Z), 2)", err: "cutRight requires 2 arguments (at position 10)"},
+		{in: "$lower()", err: "lower requires 1 argument (at position 1)"},
+
+		{in: "$regexp(Hello World!, ^[a-z+\\[$, Universe)", err: "invalid regexp pattern: ^[a-z+\\[$ (at position 1)"},
+		{in: "$regexp(Hello World!, _, ABC'()')", err: "can't mix quoted & unquoted content in function arg: regexp (at position 29)"},
+		{in: "$regexp(Hello World!, _, ')", err: "string missing closing quote (at position 26)"},
+		{in: "$regexp(Hello World!, _)", err: "regexp requires 3 arguments (at position 1)"},
+		{in: "$if(a)", err: "if requires 2 or 3 arguments (at position 1)"},
+		{in: "$ifempty(a)", err: "ifempty requires 2 arguments (at position 1)"},
+		{in: "$replace(a,b)", err: "replace requires 3 arguments (at position 1)"},
+		{in: "$pad(a,b)", err: "pad requires a number as second argument (at position 1)"},
+		{in: "$pad(a,2,bc)", err: "pad requires a single character as third argument (at position 1)"},
+		{in: "$trim(a,b)", err: "trim requires 1 argument (at position 1)"},
+		{in: "$date()", err: "date requires 1 argument (at position 1)"},
+		{in: "Trip to %_ABC%", err: "invalid token: ABC (at position 9)"},
+		{in: "%_year% - $lower(Trip", err: "function missing closing parenthesis (at position 11)"},
 	}
 
 	for _, tt := range testData {
-		_, err := parseAlbumNameTemplate(tt.in, "", timeObj)
+		_, err := parseAlbumNameTemplate(tt.in, templateData{CaptureTime: timeObj})
 		assert.EqualError(t, err, tt.err)
 	}
 }
@@ -236,3 +385,9 @@ func TestValidateAlbumNameTemplate(t *testing.T) {
 	err := ValidateAlbumNameTemplate("$lower(Hello World")
 	assert.Error(t, err)
 }
+
+func TestValidateAlbumNameLocale(t *testing.T) {
+	assert.NoError(t, ValidateAlbumNameLocale("en"))
+	assert.NoError(t, ValidateAlbumNameLocale("fr"))
+	assert.Error(t, ValidateAlbumNameLocale("xx"))
+}
@@ -0,0 +1,121 @@
+package upload
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultAlbumTitleMaxLength is the maximum length of album titles in Google Photos.
+	DefaultAlbumTitleMaxLength = 500
+
+	// albumTitleEllipsis is added to the album titles that are truncated.
+	albumTitleEllipsis = "…"
+)
+
+// AlbumTitleOptions sets how album names are sanitized.
+type AlbumTitleOptions struct {
+	// Normalize removes control characters, collapses whitespaces and trims the album names. It's optional, because
+	// existing albums are found by name.
+	Normalize bool
+	// MaxLength is the maximum length of album names, in characters. Longer names are truncated, ending with an
+	// ellipsis. If it's zero, DefaultAlbumTitleMaxLength is used.
+	MaxLength int
+	// Replacements replaces texts in album names, like "/" with "-".
+	Replacements map[string]string
+	// DefaultAlbum is the album name used when the album name of a file is empty after sanitizing it.
+	DefaultAlbum string
+}
+
+// albumTitleSanitizer sanitizes album names.
+type albumTitleSanitizer struct {
+	maxLength int
+	normalize bool
+	replacer  *strings.Replacer
+}
+
+func newAlbumTitleSanitizer(options AlbumTitleOptions) *albumTitleSanitizer {
+	s := &albumTitleSanitizer{maxLength: options.MaxLength, normalize: options.Normalize}
+	if s.maxLength <= 0 {
+		s.maxLength = DefaultAlbumTitleMaxLength
+	}
+
+	if len(options.Replacements) > 0 {
+		// Longer texts are replaced first, and texts with the same length in alphabetical order, so results are
+		// deterministic.
+		keys := make([]string, 0, len(options.Replacements))
+		for k := range options.Replacements {
+			if k != "" {
+				keys = append(keys, k)
+			}
+		}
+		slices.SortFunc(keys, func(a, b string) int {
+			return cmp.Or(cmp.Compare(len(b), len(a)), cmp.Compare(a, b))
+		})
+
+		var oldnew []string
+		for _, k := range keys {
+			oldnew = append(oldnew, k, options.Replacements[k])
+		}
+		s.replacer = strings.NewReplacer(oldnew...)
+	}
+	return s
+}
+
+// sanitize returns the album name after applying the replacements, normalizing it if it's enabled and truncating it
+// to the maximum length.
+func (s *albumTitleSanitizer) sanitize(name string) string {
+	if s.replacer != nil {
+		name = s.replacer.Replace(name)
+	}
+
+	if s.normalize {
+		name = strings.Map(func(r rune) rune {
+			if unicode.IsControl(r) && !unicode.IsSpace(r) {
+				return -1
+			}
+			return r
+		}, name)
+		name = strings.Join(strings.Fields(name), " ")
+	}
+
+	return truncateAlbumTitle(name, s.maxLength)
+}
+
+// truncateAlbumTitle truncates the album name to the maximum length, ending with an ellipsis.
+func truncateAlbumTitle(name string, maxLength int) string {
+	runes := []rune(name)
+	if len(runes) <= maxLength {
+		return name
+	}
+	ellipsis := []rune(albumTitleEllipsis)
+	if maxLength <= len(ellipsis) {
+		return string(runes[:maxLength])
+	}
+	return strings.TrimSpace(string(runes[:maxLength-len(ellipsis)])) + albumTitleEllipsis
+}
+
+// SplitAlbumTitle returns the title of the next album of a full album, adding the suffix to its title. The title is
+// truncated to leave room for the suffix, so the result is not longer than maxLength. If maxLength is zero,
+// DefaultAlbumTitleMaxLength is used.
+func SplitAlbumTitle(title string, suffix string, maxLength int) string {
+	if maxLength <= 0 {
+		maxLength = DefaultAlbumTitleMaxLength
+	}
+	room := maxLength - utf8.RuneCountInString(suffix)
+	if room <= 0 {
+		return truncateAlbumTitle(suffix, maxLength)
+	}
+	return truncateAlbumTitle(title, room) + suffix
+}
+
+// albumTitle returns the sanitized album name.
+func (job *UploadFolderJob) albumTitle(name string) string {
+	if job.albumTitles == nil {
+		job.albumTitles = newAlbumTitleSanitizer(job.AlbumTitles)
+	}
+	return job.albumTitles.sanitize(name)
+}
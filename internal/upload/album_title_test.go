@@ -0,0 +1,102 @@
+package upload
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/log"
+)
+
+func TestAlbumTitleSanitizer_Sanitize(t *testing.T) {
+	var testCases = []struct {
+		name    string
+		options AlbumTitleOptions
+		in      string
+		want    string
+	}{
+		{name: "Unchanged", in: "Summer 2024", want: "Summer 2024"},
+		{name: "NotNormalized", in: "  Summer  2024 ", want: "  Summer  2024 "},
+		{name: "Trimmed", options: AlbumTitleOptions{Normalize: true}, in: "  Summer 2024 \n", want: "Summer 2024"},
+		{name: "CollapsedWhitespaces", options: AlbumTitleOptions{Normalize: true}, in: "Summer \t  2024", want: "Summer 2024"},
+		{name: "ControlCharacters", options: AlbumTitleOptions{Normalize: true}, in: "Summer\x00\x1b 2024\x7f", want: "Summer 2024"},
+		{name: "Replacements", options: AlbumTitleOptions{Replacements: map[string]string{"/": " - ", "_": " "}}, in: "Trips/Summer_2024", want: "Trips - Summer 2024"},
+		{name: "LongerReplacementsFirst", options: AlbumTitleOptions{Replacements: map[string]string{"/": "-", "//": "+"}}, in: "a//b/c", want: "a+b-c"},
+		{name: "Truncated", options: AlbumTitleOptions{MaxLength: 10}, in: "Summer holidays 2024", want: "Summer ho…"},
+		{name: "TruncatedWithoutTrailingSpace", options: AlbumTitleOptions{MaxLength: 8}, in: "Summer holidays", want: "Summer…"},
+		{name: "TruncatedByCharacters", options: AlbumTitleOptions{MaxLength: 4}, in: "Málaga", want: "Mál…"},
+		{name: "DefaultMaxLength", in: strings.Repeat("a", 600), want: strings.Repeat("a", 499) + "…"},
+		{name: "Empty", options: AlbumTitleOptions{Normalize: true}, in: " \t ", want: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, newAlbumTitleSanitizer(tc.options).sanitize(tc.in))
+		})
+	}
+}
+
+func TestSplitAlbumTitle(t *testing.T) {
+	var testCases = []struct {
+		name      string
+		title     string
+		suffix    string
+		maxLength int
+		want      string
+	}{
+		{name: "Unchanged", title: "Summer 2024", suffix: " (2)", want: "Summer 2024 (2)"},
+		{name: "TruncatedForSuffix", title: "Summer holidays", suffix: " (2)", maxLength: 12, want: "Summer… (2)"},
+		{name: "DefaultMaxLength", title: strings.Repeat("a", 500), suffix: " (2)", want: strings.Repeat("a", 495) + "… (2)"},
+		{name: "SuffixTooLong", title: "Summer", suffix: " (part 2)", maxLength: 5, want: "(pa…"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, SplitAlbumTitle(tc.title, tc.suffix, tc.maxLength))
+		})
+	}
+}
+
+func TestUploadFolderJob_AlbumNamesAreSanitized(t *testing.T) {
+	data := templateData{Path: "Trips/IMG_0001.jpg", CaptureTime: time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)}
+
+	var testCases = []struct {
+		name string
+		job  UploadFolderJob
+		want []string
+	}{
+		{
+			name: "Replacements",
+			job:  UploadFolderJob{Album: "template:%_year%/%_month%", AlbumTitles: AlbumTitleOptions{Replacements: map[string]string{"/": "-"}}},
+			want: []string{"2024-05"},
+		},
+		{
+			name: "RepeatedAfterSanitizing",
+			job:  UploadFolderJob{Album: "name:Family", Albums: []string{"name:  Family "}, AlbumTitles: AlbumTitleOptions{Normalize: true}},
+			want: []string{"Family"},
+		},
+		{
+			name: "EmptyWithDefaultAlbum",
+			job:  UploadFolderJob{Album: "template:$replace(%_directory%,Trips,)", Albums: []string{"name:Family"}, AlbumTitles: AlbumTitleOptions{DefaultAlbum: "Unsorted"}},
+			want: []string{"Unsorted", "Family"},
+		},
+		{
+			name: "EmptyWithoutDefaultAlbum",
+			job:  UploadFolderJob{Album: "template:$replace(%_directory%,Trips,)", Albums: []string{"name:Family"}},
+			want: []string{"Family"},
+		},
+		{
+			name: "NoAlbumOption",
+			job:  UploadFolderJob{AlbumTitles: AlbumTitleOptions{DefaultAlbum: "Unsorted"}},
+			want: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.job.albumNames(data, log.Discard))
+		})
+	}
+}
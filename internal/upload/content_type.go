@@ -0,0 +1,129 @@
+package upload
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/log"
+)
+
+// contentHeaderSize is the size of the beginning of the files read to detect their content. It includes two MPEG
+// transport stream packets.
+const contentHeaderSize = 2*m2tsPacketSize + 16
+
+// contentType is a photo or video format supported by Google Photos.
+type contentType struct {
+	name string
+	// extensions are the file extensions of the format. The first one is the canonical extension.
+	extensions []string
+}
+
+var (
+	jpegContent = contentType{name: "JPEG", extensions: []string{".jpg", ".jpeg", ".jpe", ".jfif"}}
+	pngContent  = contentType{name: "PNG", extensions: []string{".png"}}
+	gifContent  = contentType{name: "GIF", extensions: []string{".gif"}}
+	webpContent = contentType{name: "WebP", extensions: []string{".webp"}}
+	heifContent = contentType{name: "HEIF", extensions: []string{".heic", ".heif", ".hif", ".avif"}}
+	rawContent  = contentType{name: "RAW", extensions: []string{
+		".dng", ".arw", ".srf", ".sr2", ".crw", ".cr2", ".cr3", ".nef", ".nrw", ".orf", ".raf", ".raw", ".rw2", ".tif", ".tiff",
+	}}
+	videoContent = contentType{name: "video", extensions: []string{
+		".mp4", ".mov", ".m4v", ".3gp", ".3g2", ".avi", ".divx", ".mkv", ".wmv", ".asf", ".mpg", ".mpeg", ".mod", ".tod",
+		".mts", ".m2ts", ".m2t",
+	}}
+)
+
+// matchesExtension returns true if the extension of the file belongs to the format.
+func (c contentType) matchesExtension(path string) bool {
+	return slices.Contains(c.extensions, strings.ToLower(filepath.Ext(path)))
+}
+
+// detectContentType returns the format of the file, using the magic numbers at its beginning.
+func detectContentType(path string) (contentType, bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return contentType{}, false, err
+	}
+	defer f.Close() //nolint:errcheck
+
+	// Short files are padded with zeros, so the header can be checked without bounds checks.
+	header := make([]byte, contentHeaderSize)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return contentType{}, false, err
+	}
+	if n < 12 {
+		return contentType{}, false, nil
+	}
+
+	c, found := contentTypeOf(header)
+	return c, found, nil
+}
+
+// contentTypeOf returns the format of a file header, of contentHeaderSize bytes.
+func contentTypeOf(header []byte) (contentType, bool) {
+	switch {
+	case isJPEGHeader(header) && header[2] == 0xFF:
+		return jpegContent, true
+	case bytes.HasPrefix(header, []byte("\x89PNG\r\n\x1a\n")):
+		return pngContent, true
+	case bytes.HasPrefix(header, []byte("GIF87a")), bytes.HasPrefix(header, []byte("GIF89a")):
+		return gifContent, true
+	case string(header[:4]) == "RIFF" && string(header[8:12]) == "WEBP":
+		return webpContent, true
+	case string(header[:4]) == "RIFF" && string(header[8:12]) == "AVI ":
+		return videoContent, true
+	case isHEIFHeader(header):
+		return heifContent, true
+	case string(header[4:8]) == "ftyp" && string(header[8:12]) == "crx ":
+		// Canon CR3 RAW files are ISO-BMFF files.
+		return rawContent, true
+	case isQuickTimeHeader(header):
+		return videoContent, true
+	case bytes.HasPrefix(header, []byte("FUJIFILMCCD-RAW")), string(header[6:14]) == "HEAPCCDR", isTIFFHeader(header):
+		return rawContent, true
+	case bytes.HasPrefix(header, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		// Matroska (MKV)
+		return videoContent, true
+	case bytes.HasPrefix(header, []byte{0x30, 0x26, 0xB2, 0x75, 0x8E, 0x66, 0xCF, 0x11}):
+		// Advanced Systems Format (ASF, WMV)
+		return videoContent, true
+	case bytes.HasPrefix(header, []byte{0x00, 0x00, 0x01, 0xBA}), bytes.HasPrefix(header, []byte{0x00, 0x00, 0x01, 0xB3}):
+		// MPEG program and elementary streams (MPG, MOD, TOD)
+		return videoContent, true
+	case header[0] == 0x47 && header[tsPacketSize] == 0x47, header[4] == 0x47 && header[4+m2tsPacketSize] == 0x47:
+		// MPEG transport streams (MTS, M2TS)
+		return videoContent, true
+	}
+	return contentType{}, false
+}
+
+// isAllowedByContent returns true if the file is allowed by the Filter using its content instead of its extension.
+// Files whose content is not a supported format are not allowed, and files whose extension doesn't match their
+// content are reported.
+func (job *UploadFolderJob) isAllowedByContent(fp string, relativePath string, logger log.Logger) bool {
+	allowed := job.Filter.IsAllowed(relativePath)
+
+	c, found, err := detectContentType(fp)
+	if err != nil || !found {
+		if allowed {
+			logger.Warnf("Skipping file '%s', its content is not a supported photo or video.", fp)
+		}
+		return false
+	}
+
+	if c.matchesExtension(relativePath) {
+		return allowed
+	}
+
+	// The canonical extension of the format is used to check the patterns, like "**/*.jpg".
+	allowedByContent := job.Filter.IsAllowed(strings.TrimSuffix(relativePath, filepath.Ext(relativePath)) + c.extensions[0])
+	if allowed || allowedByContent {
+		logger.Warnf("File '%s' is a %s file, but its extension doesn't match its content.", fp, c.name)
+	}
+	return allowedByContent
+}
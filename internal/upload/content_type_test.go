@@ -0,0 +1,79 @@
+package upload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentTypeOf(t *testing.T) {
+	mts := make([]byte, contentHeaderSize)
+	mts[4], mts[4+m2tsPacketSize] = 0x47, 0x47
+
+	var testCases = []struct {
+		name   string
+		header []byte
+		want   string
+	}{
+		{name: "JPEG", header: []byte{0xFF, 0xD8, 0xFF, 0xE1}, want: "JPEG"},
+		{name: "PNG", header: []byte("\x89PNG\r\n\x1a\n"), want: "PNG"},
+		{name: "GIF", header: []byte("GIF89a"), want: "GIF"},
+		{name: "WebP", header: []byte("RIFF\x00\x00\x00\x00WEBPVP8 "), want: "WebP"},
+		{name: "AVI", header: []byte("RIFF\x00\x00\x00\x00AVI LIST"), want: "video"},
+		{name: "HEIC", header: []byte("\x00\x00\x00\x18ftypheic"), want: "HEIF"},
+		{name: "AVIF", header: []byte("\x00\x00\x00\x18ftypavif"), want: "HEIF"},
+		{name: "CR3", header: []byte("\x00\x00\x00\x18ftypcrx "), want: "RAW"},
+		{name: "MP4", header: []byte("\x00\x00\x00\x18ftypisom"), want: "video"},
+		{name: "MOV", header: []byte("\x00\x00\x00\x14ftypqt  "), want: "video"},
+		{name: "TIFF-based RAW", header: []byte("II*\x00\x08\x00\x00\x00"), want: "RAW"},
+		{name: "ORF", header: []byte("IIRO\x08\x00\x00\x00"), want: "RAW"},
+		{name: "RAF", header: []byte("FUJIFILMCCD-RAW 0201"), want: "RAW"},
+		{name: "CRW", header: []byte("II\x1a\x00\x00\x00HEAPCCDR"), want: "RAW"},
+		{name: "MKV", header: []byte{0x1A, 0x45, 0xDF, 0xA3}, want: "video"},
+		{name: "WMV", header: []byte{0x30, 0x26, 0xB2, 0x75, 0x8E, 0x66, 0xCF, 0x11}, want: "video"},
+		{name: "MPG", header: []byte{0x00, 0x00, 0x01, 0xBA}, want: "video"},
+		{name: "M2TS", header: mts, want: "video"},
+		{name: "SVG", header: []byte("<?xml version=\"1.0\"?><svg"), want: ""},
+		{name: "Text", header: []byte("Lorem ipsum dolor sit amet"), want: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			header := make([]byte, contentHeaderSize)
+			copy(header, tc.header)
+
+			c, found := contentTypeOf(header)
+			assert.Equal(t, tc.want != "", found)
+			assert.Equal(t, tc.want, c.name)
+		})
+	}
+}
+
+func TestDetectContentType(t *testing.T) {
+	var testCases = []struct {
+		path string
+		want string
+	}{
+		{path: "testdata/SampleJPGImage.jpg", want: "JPEG"},
+		{path: "testdata/SamplePNGImage.png", want: "PNG"},
+		{path: "testdata/SampleVideo.mp4", want: "video"},
+		{path: "testdata/SampleSVGImage.svg", want: ""},
+		{path: "testdata/SampleText.txt", want: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			c, found, err := detectContentType(tc.path)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want != "", found)
+			assert.Equal(t, tc.want, c.name)
+		})
+	}
+}
+
+func TestContentType_MatchesExtension(t *testing.T) {
+	assert.True(t, jpegContent.matchesExtension("a/IMG_0001.JPEG"))
+	assert.False(t, jpegContent.matchesExtension("a/IMG_0001.png"))
+	assert.False(t, jpegContent.matchesExtension("a/IMG_0001"))
+}
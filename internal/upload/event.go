@@ -0,0 +1,113 @@
+package upload
+
+import (
+	"slices"
+	"sort"
+	"time"
+)
+
+const (
+	// EventAlbumOption is the Album option value that groups the files of every folder in events.
+	// A new event starts when the time between two consecutive files is longer than the EventGap.
+	EventAlbumOption = "auto:event"
+
+	// DefaultEventGap is the default time between files to start a new event.
+	DefaultEventGap = 8 * time.Hour
+
+	// DefaultEventAlbumName is the default template of the event album names, like "Paris 2024-05-01". It only uses
+	// the start of the event, so the name doesn't change when new files extend the event.
+	DefaultEventAlbumName = "%_directory% %_event_start%"
+)
+
+// event is a group of files taken close in time.
+type event struct {
+	start time.Time
+	end   time.Time
+}
+
+// eventClusters groups the files of every folder in events.
+type eventClusters struct {
+	// times are the capture times of the files, indexed by folder.
+	times map[string][]time.Time
+	// events are the events of every folder, sorted by start time.
+	events map[string][]event
+}
+
+func newEventClusters() *eventClusters {
+	return &eventClusters{
+		times:  make(map[string][]time.Time),
+		events: make(map[string][]event),
+	}
+}
+
+// add adds a file of the folder. Already uploaded files must be added too, so events keep the same names.
+func (c *eventClusters) add(dir string, captureTime time.Time) {
+	c.times[dir] = append(c.times[dir], captureTime)
+}
+
+// cluster groups the files of every folder in events. A new event starts after the given gap without files.
+func (c *eventClusters) cluster(gap time.Duration) {
+	for dir, times := range c.times {
+		slices.SortFunc(times, func(a, b time.Time) int { return a.Compare(b) })
+
+		var events []event
+		for _, t := range times {
+			if n := len(events); n > 0 && t.Sub(events[n-1].end) <= gap {
+				events[n-1].end = t
+				continue
+			}
+			events = append(events, event{start: t, end: t})
+		}
+		c.events[dir] = events
+	}
+}
+
+// find returns the event of the folder that includes the given time.
+func (c *eventClusters) find(dir string, t time.Time) (event, bool) {
+	events := c.events[dir]
+	i := sort.Search(len(events), func(i int) bool { return !events[i].end.Before(t) })
+	if i < len(events) && !events[i].start.After(t) {
+		return events[i], true
+	}
+	return event{}, false
+}
+
+// usesEvents returns true if the file is added to an event album.
+func (job *UploadFolderJob) usesEvents(data templateData) bool {
+	return job.albumOption(data) == EventAlbumOption || slices.Contains(job.Albums, EventAlbumOption)
+}
+
+// mayUseEvents returns true if any file could be added to an event album. It avoids reading the metadata of already
+// uploaded files when events are not used.
+func (job *UploadFolderJob) mayUseEvents() bool {
+	if job.Album == EventAlbumOption || slices.Contains(job.Albums, EventAlbumOption) {
+		return true
+	}
+	for _, rule := range job.AlbumRules {
+		if rule.Album == EventAlbumOption {
+			return true
+		}
+	}
+	for _, option := range job.albumOverrides {
+		if option == EventAlbumOption {
+			return true
+		}
+	}
+	return false
+}
+
+// eventGap returns the time between files to start a new event.
+func (job *UploadFolderJob) eventGap() time.Duration {
+	if job.EventGap > 0 {
+		return job.EventGap
+	}
+	return DefaultEventGap
+}
+
+// eventAlbumName returns the template of the event album names.
+func (job *UploadFolderJob) eventAlbumName() string {
+	if job.EventAlbumName != "" {
+		return job.EventAlbumName
+	}
+	return DefaultEventAlbumName
+}
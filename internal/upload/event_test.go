@@ -0,0 +1,42 @@
+package upload
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventClusters(t *testing.T) {
+	at := func(day, hour int) time.Time { return time.Date(2024, time.May, day, hour, 0, 0, 0, time.UTC) }
+
+	c := newEventClusters()
+	for _, t := range []time.Time{at(2, 20), at(1, 10), at(2, 9), at(1, 18), at(10, 12)} {
+		c.add("trip", t)
+	}
+	c.add("work", at(2, 9))
+	c.cluster(16 * time.Hour)
+
+	var testCases = []struct {
+		name  string
+		dir   string
+		time  time.Time
+		want  event
+		found bool
+	}{
+		{name: "FirstFileOfEvent", dir: "trip", time: at(1, 10), want: event{start: at(1, 10), end: at(2, 20)}, found: true},
+		{name: "LastFileOfEvent", dir: "trip", time: at(2, 20), want: event{start: at(1, 10), end: at(2, 20)}, found: true},
+		{name: "EventAfterGap", dir: "trip", time: at(10, 12), want: event{start: at(10, 12), end: at(10, 12)}, found: true},
+		{name: "OtherFolder", dir: "work", time: at(2, 9), want: event{start: at(2, 9), end: at(2, 9)}, found: true},
+		{name: "TimeBetweenEvents", dir: "trip", time: at(5, 12), found: false},
+		{name: "UnknownFolder", dir: "home", time: at(2, 9), found: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, found := c.find(tc.dir, tc.time)
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package upload
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// EXIF tags used to get the file metadata.
+// See https://www.cipa.jp/std/documents/e/DC-008-2012_E.pdf.
+const (
+	exifTagMake               = 0x010F
+	exifTagModel              = 0x0110
+	exifTagDateTime           = 0x0132
+	exifTagExifIFDPointer     = 0x8769
+	exifTagDateTimeOriginal   = 0x9003
+	exifTagDateTimeDigitized  = 0x9004
+	exifTagOffsetTimeOriginal = 0x9011
+)
+
+const (
+	exifTypeASCII = 2
+	exifTypeLong  = 4
+
+	// maxIFDEntries avoids reading corrupted IFDs.
+	maxIFDEntries = 1000
+)
+
+// jpegMetadata returns the EXIF metadata of a JPEG file.
+func jpegMetadata(r io.ReadSeeker) (fileMetadata, error) {
+	// Skips the SOI marker.
+	if _, err := r.Seek(2, io.SeekStart); err != nil {
+		return fileMetadata{}, err
+	}
+	tiff, err := jpegExifSegment(r)
+	if err != nil {
+		return fileMetadata{}, err
+	}
+	return tiffMetadata(bytes.NewReader(tiff))
+}
+
+// isJPEGHeader returns true if the header belongs to a JPEG file.
+func isJPEGHeader(header []byte) bool {
+	return header[0] == 0xFF && header[1] == 0xD8
+}
+
+// isTIFFHeader returns true if the header belongs to a TIFF-based file, like most RAW formats.
+// Olympus (ORF) and Panasonic (RW2) RAW files use their own magic numbers.
+func isTIFFHeader(header []byte) bool {
+	switch string(header[:4]) {
+	case "II*\x00", "MM\x00*", "IIRO", "IIRS", "IIU\x00":
+		return true
+	}
+	return false
+}
+
+// jpegExifSegment returns the TIFF structure of the EXIF APP1 segment of a JPEG file.
+// The reader must be positioned after the SOI marker.
+func jpegExifSegment(r io.ReadSeeker) ([]byte, error) {
+	marker := make([]byte, 2)
+	for {
+		if _, err := io.ReadFull(r, marker); err != nil {
+			return nil, errNoMetadata
+		}
+		if marker[0] != 0xFF {
+			return nil, fmt.Errorf("invalid JPEG marker")
+		}
+
+		switch {
+		case marker[1] == 0xFF:
+			// Fill byte, the marker starts in the next byte.
+			if _, err := r.Seek(-1, io.SeekCurrent); err != nil {
+				return nil, err
+			}
+			continue
+		case marker[1] >= 0xD0 && marker[1] <= 0xD7, marker[1] == 0x01:
+			// Markers without payload.
+			continue
+		case marker[1] == 0xDA, marker[1] == 0xD9:
+			// Image data starts (SOS) or ends (EOI) before the EXIF segment has been found.
+			return nil, errNoMetadata
+		}
+
+		length := make([]byte, 2)
+		if _, err := io.ReadFull(r, length); err != nil {
+			return nil, errNoMetadata
+		}
+		size := int64(binary.BigEndian.Uint16(length)) - 2
+		if size < 0 {
+			return nil, fmt.Errorf("invalid JPEG segment length")
+		}
+
+		if marker[1] != 0xE1 {
+			if _, err := r.Seek(size, io.SeekCurrent); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		segment := make([]byte, size)
+		if _, err := io.ReadFull(r, segment); err != nil {
+			return nil, errNoMetadata
+		}
+		// APP1 is also used by XMP, so the EXIF segment is the one with the EXIF header.
+		if bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:], nil
+		}
+	}
+}
+
+// ifdEntry is an entry of a TIFF Image File Directory.
+type ifdEntry struct {
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+// tiffReader reads the IFDs of a TIFF structure.
+type tiffReader struct {
+	r     io.ReaderAt
+	order binary.ByteOrder
+}
+
+// tiffMetadata returns the metadata of the TIFF structure. The capture time is DateTimeOriginal, falling back to
+// DateTimeDigitized and DateTime.
+func tiffMetadata(r io.ReaderAt) (fileMetadata, error) {
+	header := make([]byte, 8)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return fileMetadata{}, errNoMetadata
+	}
+
+	t := tiffReader{r: r}
+	switch string(header[:2]) {
+	case "II":
+		t.order = binary.LittleEndian
+	case "MM":
+		t.order = binary.BigEndian
+	default:
+		return fileMetadata{}, fmt.Errorf("invalid TIFF byte order")
+	}
+
+	ifd0, err := t.readIFD(t.order.Uint32(header[4:]))
+	if err != nil {
+		return fileMetadata{}, err
+	}
+
+	var exifIFD map[uint16]ifdEntry
+	if e, ok := ifd0[exifTagExifIFDPointer]; ok && e.typ == exifTypeLong {
+		if exifIFD, err = t.readIFD(t.order.Uint32(e.value)); err != nil {
+			return fileMetadata{}, err
+		}
+	}
+
+	metadata := fileMetadata{
+		cameraMake:  t.readASCII(ifd0[exifTagMake]),
+		cameraModel: t.readASCII(ifd0[exifTagModel]),
+	}
+
+	offset := t.readASCII(exifIFD[exifTagOffsetTimeOriginal])
+	for _, e := range []ifdEntry{exifIFD[exifTagDateTimeOriginal], exifIFD[exifTagDateTimeDigitized], ifd0[exifTagDateTime]} {
+		if captureTime, err := parseExifDateTime(t.readASCII(e), offset); err == nil {
+			metadata.captureTime = captureTime
+			break
+		}
+	}
+
+	if metadata == (fileMetadata{}) {
+		return fileMetadata{}, errNoMetadata
+	}
+	return metadata, nil
+}
+
+// readIFD returns the entries of the IFD at the given offset, indexed by tag.
+func (t tiffReader) readIFD(offset uint32) (map[uint16]ifdEntry, error) {
+	count := make([]byte, 2)
+	if _, err := t.r.ReadAt(count, int64(offset)); err != nil {
+		return nil, errNoMetadata
+	}
+	n := int(t.order.Uint16(count))
+	if n > maxIFDEntries {
+		return nil, fmt.Errorf("invalid IFD entries count: %d", n)
+	}
+
+	buf := make([]byte, n*12)
+	if _, err := t.r.ReadAt(buf, int64(offset)+2); err != nil {
+		return nil, errNoMetadata
+	}
+
+	entries := make(map[uint16]ifdEntry, n)
+	for i := 0; i < n; i++ {
+		b := buf[i*12 : (i+1)*12]
+		entries[t.order.Uint16(b[0:])] = ifdEntry{
+			typ:   t.order.Uint16(b[2:]),
+			count: t.order.Uint32(b[4:]),
+			value: b[8:12],
+		}
+	}
+	return entries, nil
+}
+
+// readASCII returns the value of an ASCII entry, or an empty string if it's not an ASCII entry.
+func (t tiffReader) readASCII(e ifdEntry) string {
+	// Camera names, dates and time offsets are short strings, longer values are ignored.
+	if e.typ != exifTypeASCII || e.count == 0 || e.count > 64 {
+		return ""
+	}
+
+	value := e.value
+	if e.count > 4 {
+		value = make([]byte, e.count)
+		if _, err := t.r.ReadAt(value, int64(t.order.Uint32(e.value))); err != nil {
+			return ""
+		}
+	}
+	return strings.TrimRight(string(value[:e.count]), "\x00 ")
+}
+
+// parseExifDateTime parses an EXIF date, like "2021:03:14 10:15:00". The offset, like "+01:00", is optional.
+// Dates without offset use the local time zone, like file modification times.
+func parseExifDateTime(value string, offset string) (time.Time, error) {
+	if offset != "" {
+		if t, err := time.Parse("2006:01:02 15:04:05-07:00", value+offset); err == nil {
+			return t, nil
+		}
+	}
+	return time.ParseInLocation("2006:01:02 15:04:05", value, time.Local)
+}
@@ -0,0 +1,111 @@
+package upload
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadMetadata_JPEG(t *testing.T) {
+	got, err := readMetadata("testdata/SampleJPGImage.jpg")
+
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2017, time.September, 11, 22, 15, 46, 0, time.Local), got.captureTime)
+}
+
+func TestReadMetadata_TIFF(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		order  binary.ByteOrder
+		offset string
+		want   time.Time
+	}{
+		{name: "LittleEndian", order: binary.LittleEndian, want: time.Date(2021, time.March, 14, 10, 15, 0, 0, time.Local)},
+		{name: "BigEndian", order: binary.BigEndian, want: time.Date(2021, time.March, 14, 10, 15, 0, 0, time.Local)},
+		{name: "WithOffsetTime", order: binary.LittleEndian, offset: "+02:00", want: time.Date(2021, time.March, 14, 8, 15, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "image.dng")
+			require.NoError(t, os.WriteFile(path, buildTIFF(tc.order, "2021:03:14 10:15:00", tc.offset), 0600))
+
+			metadata, err := readMetadata(path)
+			got := metadata.captureTime
+
+			require.NoError(t, err)
+			assert.True(t, tc.want.Equal(got), "want %s, got %s", tc.want, got)
+			assert.Equal(t, "Canon", metadata.cameraMake)
+			assert.Equal(t, "Canon EOS R5", metadata.cameraModel)
+		})
+	}
+}
+
+func TestReadMetadata_ReturnsErrorWithoutMetadata(t *testing.T) {
+	var testCases = []string{
+		"testdata/ScreenShotJPG.jpg",
+		"testdata/SamplePNGImage.png",
+		"testdata/SampleText.txt",
+	}
+
+	for _, path := range testCases {
+		_, err := readMetadata(path)
+		assert.ErrorIs(t, err, errNoMetadata, path)
+	}
+}
+
+// buildTIFF returns a TIFF structure with the Make and Model tags, and an EXIF IFD containing the DateTimeOriginal
+// and OffsetTimeOriginal tags.
+func buildTIFF(order binary.ByteOrder, dateTime string, offset string) []byte {
+	const (
+		cameraMake    = "Canon\x00"
+		cameraModel   = "Canon EOS R5\x00"
+		exifIFDOffset = 8 + 42
+		dataOffset    = exifIFDOffset + 30
+	)
+
+	var buf bytes.Buffer
+	write := func(v interface{}) { _ = binary.Write(&buf, order, v) }
+
+	// Header
+	if order == binary.LittleEndian {
+		buf.WriteString("II")
+	} else {
+		buf.WriteString("MM")
+	}
+	write(uint16(42))
+	write(uint32(8))
+
+	dateTime += "\x00"
+	offset += "\x00"
+	makeOffset := dataOffset + len(dateTime) + len(offset)
+
+	// IFD0
+	write(uint16(3))
+	write([]uint16{exifTagMake, exifTypeASCII})
+	write([]uint32{uint32(len(cameraMake)), uint32(makeOffset)})
+	write([]uint16{exifTagModel, exifTypeASCII})
+	write([]uint32{uint32(len(cameraModel)), uint32(makeOffset + len(cameraMake))})
+	write([]uint16{exifTagExifIFDPointer, exifTypeLong})
+	write([]uint32{1, exifIFDOffset, 0})
+
+	// EXIF IFD
+	write(uint16(2))
+	write([]uint16{exifTagDateTimeOriginal, exifTypeASCII})
+	write([]uint32{uint32(len(dateTime)), dataOffset})
+	write([]uint16{exifTagOffsetTimeOriginal, exifTypeASCII})
+	write([]uint32{uint32(len(offset)), uint32(dataOffset + len(dateTime))})
+	write(uint32(0))
+
+	buf.WriteString(dateTime)
+	buf.WriteString(offset)
+	buf.WriteString(cameraMake)
+	buf.WriteString(cameraModel)
+	return buf.Bytes()
+}
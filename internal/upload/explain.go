@@ -0,0 +1,312 @@
+package upload
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/log"
+)
+
+// FileExplanation explains why a file is uploaded or skipped, and where it's uploaded.
+type FileExplanation struct {
+	Path         string
+	RelativePath string
+
+	// Steps are the checks of the file, in the order they are done while scanning the source folder.
+	Steps []ExplanationStep
+
+	// Uploaded is true if the FileTracker considers the file already uploaded.
+	Uploaded bool
+	// PendingMediaItemID is the media item of an uploaded file that was not added to all its extra albums yet.
+	// These files are only added to their extra albums by the next push.
+	PendingMediaItemID string
+
+	// AlbumName, ExtraAlbumNames and AlbumID are the albums of the file. They are calculated even if the file is
+	// skipped.
+	AlbumName       string
+	ExtraAlbumNames []string
+	AlbumID         string
+}
+
+// ExplanationStep is a check of a file.
+type ExplanationStep struct {
+	Name   string
+	Result string
+	// Skips is true if the file is skipped by this check.
+	Skips bool
+}
+
+// SkippedBy returns the first step that skips the file, if any.
+func (e FileExplanation) SkippedBy() (ExplanationStep, bool) {
+	for _, step := range e.Steps {
+		if step.Skips {
+			return step, true
+		}
+	}
+	return ExplanationStep{}, false
+}
+
+func (e *FileExplanation) add(name string, skips bool, format string, a ...any) {
+	e.Steps = append(e.Steps, ExplanationStep{Name: name, Result: fmt.Sprintf(format, a...), Skips: skips})
+}
+
+// ExplainFile explains the decisions taken for the file while scanning the source folder: the excluded parent
+// folders, the matching patterns and ignore rules, the file conditions, the FileTracker state, the pairing
+// policies and its albums. Event albums and pairs are calculated using the files of its folder.
+func (job *UploadFolderJob) ExplainFile(path string) (FileExplanation, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return FileExplanation{}, err
+	}
+	if fi.IsDir() {
+		return FileExplanation{}, fmt.Errorf("'%s' is a folder", path)
+	}
+	rel, err := filepath.Rel(job.SourceFolder, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return FileExplanation{}, fmt.Errorf("'%s' is not in the source folder '%s'", path, job.SourceFolder)
+	}
+
+	if err := job.compileAlbumTemplates(); err != nil {
+		return FileExplanation{}, err
+	}
+	job.albumOverrides = make(map[string]string)
+	job.ignoreRules = make(map[string][]ignoreRule)
+	job.events = newEventClusters()
+	job.siblings = make(map[string][]string)
+
+	e := FileExplanation{Path: path, RelativePath: rel}
+	now := time.Now()
+
+	job.explainParentFolders(&e)
+
+	if name := fi.Name(); name == AlbumOverrideFilename || name == IgnoreFilename {
+		e.add("Name", true, "'%s' files are not uploaded", name)
+	}
+
+	switch dir, rule, found := job.matchingIgnoreRule(rel, false); {
+	case !found:
+		e.add("Ignore files", false, "no rule matches it")
+	case rule.negate:
+		e.add("Ignore files", false, "included again by rule '%s' of '%s'", rule, filepath.Join(dir, IgnoreFilename))
+	default:
+		e.add("Ignore files", true, "ignored by rule '%s' of '%s'", rule, filepath.Join(dir, IgnoreFilename))
+	}
+
+	job.explainPatterns(&e, path)
+
+	if mismatch := job.Conditions.fileMismatch(fi.Size(), fi.ModTime(), now); mismatch != "" {
+		e.add("Size and age", true, "%s", mismatch)
+	} else {
+		e.add("Size and age", false, "meets the conditions")
+	}
+
+	metadata := job.readFileMetadata(path, fi.ModTime())
+	if mismatch := job.Conditions.captureTimeMismatch(metadata.captureTime); mismatch != "" {
+		e.add("Capture date", true, "%s", mismatch)
+	} else {
+		e.add("Capture date", false, "%s", metadata.captureTime.Format(time.DateTime))
+	}
+
+	e.Uploaded = job.isTrackedAsUploaded(path, fi.ModTime())
+	if e.Uploaded {
+		e.PendingMediaItemID = job.pendingMediaItem(path)
+	}
+	switch {
+	case e.PendingMediaItemID != "":
+		e.add("File tracker", false, "uploaded, pending extra albums (media item %s)", e.PendingMediaItemID)
+	case e.Uploaded:
+		e.add("File tracker", true, "already uploaded")
+	default:
+		e.add("File tracker", false, "not uploaded yet")
+	}
+
+	if job.pairsSiblings() {
+		job.addFolderSiblings(filepath.Dir(path), rel, now)
+		if sibling, found := job.pairedWith(rel); found {
+			e.add("Pairing", true, "paired with '%s'", sibling)
+		} else {
+			e.add("Pairing", false, "not paired")
+		}
+	}
+
+	data := job.templateData(rel, fi.Size(), metadata)
+	if job.usesEvents(data) {
+		dir := filepath.Dir(rel)
+		job.addFolderEvents(filepath.Dir(path), dir, now)
+		job.events.cluster(job.eventGap())
+		if ev, found := job.events.find(dir, data.CaptureTime); found {
+			data.EventStart, data.EventEnd = ev.start, ev.end
+		}
+	}
+	if names := job.albumNames(data, log.Discard); len(names) > 0 {
+		e.AlbumName = names[0]
+		e.ExtraAlbumNames = names[1:]
+	}
+	e.AlbumID = job.mappedAlbumID(rel, e.AlbumName)
+
+	return e, nil
+}
+
+// explainParentFolders checks if any parent folder of the file is excluded, reading their ignore and album
+// override files.
+func (job *UploadFolderJob) explainParentFolders(e *FileExplanation) {
+	var dirs []string
+	for dir := filepath.Dir(e.RelativePath); dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+		dirs = append(dirs, dir)
+	}
+	dirs = append(dirs, ".")
+	slices.Reverse(dirs)
+
+	for _, dir := range dirs {
+		if dir != "." {
+			if job.Filter.IsExcluded(dir) {
+				e.add("Parent folders", true, "folder '%s' is %s", dir, job.patternResult(dir))
+				return
+			}
+			if ignoreDir, rule, found := job.matchingIgnoreRule(dir, true); found && !rule.negate {
+				e.add("Parent folders", true, "folder '%s' is ignored by rule '%s' of '%s'", dir, rule, filepath.Join(ignoreDir, IgnoreFilename))
+				return
+			}
+		}
+
+		fp := filepath.Join(job.SourceFolder, dir)
+		if rules, err := job.readIgnoreFile(fp); err == nil && len(rules) > 0 {
+			job.ignoreRules[dir] = rules
+		}
+		if option, found, err := job.readAlbumOverride(fp); err == nil && found {
+			job.albumOverrides[dir] = option
+		}
+	}
+	e.add("Parent folders", false, "not excluded")
+}
+
+// isTrackedAsUploaded checks if the file was already uploaded like ScanFolder does, comparing its modification
+// time and hash with the stored ones, but without updating the stored modification time. Files skipped by a pairing
+// policy are not considered uploaded.
+func (job *UploadFolderJob) isTrackedAsUploaded(path string, modTime time.Time) bool {
+	reader, ok := job.FileTracker.(trackedFileReader)
+	if !ok {
+		return job.FileTracker.IsUploaded(path)
+	}
+
+	stored, found := reader.TrackedFile(path)
+	if !found || stored.PairedWith != "" {
+		return false
+	}
+	if stored.ModTime.Equal(modTime) {
+		return true
+	}
+	hash, err := reader.Hash(path)
+	return err == nil && hash == stored.Hash
+}
+
+// explainPatterns checks if the file is allowed by the Filter, using its content if DetectByContent is set.
+func (job *UploadFolderJob) explainPatterns(e *FileExplanation, path string) {
+	if !job.DetectByContent {
+		e.add("Patterns", !job.Filter.IsAllowed(e.RelativePath), "%s", job.patternResult(e.RelativePath))
+		return
+	}
+
+	c, found, err := detectContentType(path)
+	if err != nil || !found {
+		e.add("Content", true, "not a supported photo or video")
+		return
+	}
+	if c.matchesExtension(e.RelativePath) {
+		e.add("Content", false, "%s file", c.name)
+		e.add("Patterns", !job.Filter.IsAllowed(e.RelativePath), "%s", job.patternResult(e.RelativePath))
+		return
+	}
+
+	e.add("Content", false, "%s file, but its extension doesn't match its content", c.name)
+	canonical := strings.TrimSuffix(e.RelativePath, filepath.Ext(e.RelativePath)) + c.extensions[0]
+	e.add("Patterns", !job.Filter.IsAllowed(canonical), "%s, using the '%s' extension", job.patternResult(canonical), c.extensions[0])
+}
+
+// patternResult returns the pattern deciding if the path is allowed, if the Filter can tell it.
+func (job *UploadFolderJob) patternResult(relativePath string) string {
+	explainer, ok := job.Filter.(filterExplainer)
+	if !ok {
+		if job.Filter.IsAllowed(relativePath) {
+			return "included by the patterns"
+		}
+		return "excluded by the patterns"
+	}
+
+	pattern, include, found := explainer.Explain(relativePath)
+	switch {
+	case !found:
+		return "not included by any pattern"
+	case include:
+		return fmt.Sprintf("included by pattern '%s'", pattern)
+	}
+	return fmt.Sprintf("excluded by pattern '%s'", pattern)
+}
+
+// addFolderEvents adds the files of the folder to the events, like ScanFolder does.
+func (job *UploadFolderJob) addFolderEvents(dir string, relativeDir string, now time.Time) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		fp := filepath.Join(dir, entry.Name())
+		relativePath := filepath.Join(relativeDir, entry.Name())
+		fi, allowed := job.isAllowedFile(fp, relativePath, now)
+		if !allowed {
+			continue
+		}
+
+		metadata := job.readFileMetadata(fp, fi.ModTime())
+		if !job.Conditions.matchesCaptureTime(metadata.captureTime) {
+			continue
+		}
+		data := job.templateData(relativePath, fi.Size(), metadata)
+		if job.usesEvents(data) {
+			job.events.add(relativeDir, metadata.captureTime)
+		}
+	}
+}
+
+// addFolderSiblings adds the files of the folder that can be paired with the file, like ScanFolder does.
+func (job *UploadFolderJob) addFolderSiblings(dir string, relativePath string, now time.Time) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	key := siblingKey(relativePath)
+	for _, entry := range entries {
+		siblingPath := filepath.Join(filepath.Dir(relativePath), entry.Name())
+		if siblingKey(siblingPath) != key {
+			continue
+		}
+		if _, allowed := job.isAllowedFile(filepath.Join(dir, entry.Name()), siblingPath, now); allowed {
+			job.addSibling(siblingPath)
+		}
+	}
+}
+
+// isAllowedFile returns true if the file is not ignored and it's allowed by the Filter and the size and age
+// conditions, like ScanFolder does.
+func (job *UploadFolderJob) isAllowedFile(fp string, relativePath string, now time.Time) (os.FileInfo, bool) {
+	fi, err := os.Stat(fp)
+	if err != nil || fi.IsDir() || fi.Name() == AlbumOverrideFilename || fi.Name() == IgnoreFilename {
+		return nil, false
+	}
+
+	if job.isIgnored(relativePath, false) {
+		return nil, false
+	}
+	if job.DetectByContent {
+		if !job.isAllowedByContent(fp, relativePath, log.Discard) {
+			return nil, false
+		}
+	} else if !job.Filter.IsAllowed(relativePath) {
+		return nil, false
+	}
+	return fi, job.Conditions.matchesFile(fi.Size(), fi.ModTime(), now)
+}
@@ -0,0 +1,163 @@
+package upload_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/datastore/filetracker"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/filter"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/mock"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/upload"
+)
+
+func TestUploadFolderJob_ExplainFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		upload.IgnoreFilename:           "*.tmp\n",
+		"a.jpg":                         "",
+		"a.tmp":                         "",
+		"b.png":                         "",
+		"Private/c.jpg":                 "",
+		"trip/d.jpg":                    "",
+		"trip/" + upload.IgnoreFilename: "!keep.tmp\n",
+		"trip/keep.tmp":                 "",
+		"IMG_0001.CR3":                  "",
+		"IMG_0001.JPG":                  "",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+
+	testCases := []struct {
+		name          string
+		path          string
+		uploaded      bool
+		pairing       string
+		wantSkippedBy string
+		wantResult    string
+		wantAlbum     string
+	}{
+		{name: "Should upload file", path: "trip/d.jpg", wantAlbum: "trip"},
+		{name: "Should skip file in excluded folder", path: "Private/c.jpg", wantSkippedBy: "Parent folders", wantResult: "folder 'Private' is excluded by pattern '**/Private'"},
+		{name: "Should skip ignored file", path: "a.tmp", wantSkippedBy: "Ignore files", wantResult: "ignored by rule '*.tmp' of '.gphotosignore'"},
+		{name: "Should upload file included again by ignore file", path: "trip/keep.tmp", wantAlbum: "trip"},
+		{name: "Should skip file excluded by patterns", path: "b.png", wantSkippedBy: "Patterns", wantResult: "excluded by pattern '**/*.png'"},
+		{name: "Should skip uploaded file", path: "a.jpg", uploaded: true, wantSkippedBy: "File tracker", wantResult: "already uploaded"},
+		{name: "Should skip paired file", path: "IMG_0001.CR3", pairing: upload.PreferJPEGPairing, wantSkippedBy: "Pairing", wantResult: "paired with 'IMG_0001.JPG'"},
+		{name: "Should upload preferred file", path: "IMG_0001.JPG", pairing: upload.PreferJPEGPairing, wantAlbum: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u := upload.UploadFolderJob{
+				FileTracker:  &mock.FileTracker{IsUploadedFn: func(path string) bool { return tc.uploaded }},
+				SourceFolder: dir,
+				Album:        "template:%_directory%",
+				Filter:       filter.MustCompile([]string{"_ALL_FILES_"}, []string{"**/Private", "**/*.png"}),
+
+				RawJpegPairing: tc.pairing,
+			}
+
+			got, err := u.ExplainFile(filepath.Join(dir, tc.path))
+			require.NoError(t, err)
+
+			step, skipped := got.SkippedBy()
+			if tc.wantSkippedBy == "" {
+				assert.False(t, skipped, "skipped by %s: %s", step.Name, step.Result)
+				assert.Equal(t, tc.wantAlbum, got.AlbumName)
+				return
+			}
+			require.True(t, skipped)
+			assert.Equal(t, tc.wantSkippedBy, step.Name)
+			assert.Equal(t, tc.wantResult, step.Result)
+		})
+	}
+
+	t.Run("Should fail for files outside the source folder", func(t *testing.T) {
+		u := upload.UploadFolderJob{SourceFolder: filepath.Join(dir, "trip"), Filter: filter.MustCompile(nil, nil)}
+		_, err := u.ExplainFile(filepath.Join(dir, "a.jpg"))
+		assert.Error(t, err)
+	})
+}
+
+func TestUploadFolderJob_ExplainFileDoesNotUpdateTheFileTracker(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.jpg")
+	require.NoError(t, os.WriteFile(path, []byte("content"), 0600))
+
+	repo := &readOnlyRepository{t: t}
+	tracker := filetracker.New(repo)
+	hash, err := tracker.Hash(path)
+	require.NoError(t, err)
+	// The stored modification time is outdated, so the hash is compared.
+	repo.item = filetracker.TrackedFile{ModTime: time.Unix(0, 1), Hash: hash}
+
+	u := upload.UploadFolderJob{
+		FileTracker:  tracker,
+		SourceFolder: dir,
+		Filter:       filter.MustCompile([]string{"_ALL_FILES_"}, nil),
+	}
+
+	got, err := u.ExplainFile(path)
+	require.NoError(t, err)
+	assert.True(t, got.Uploaded)
+}
+
+func TestUploadFolderJob_ExplainFilePendingExtraAlbums(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.jpg")
+	require.NoError(t, os.WriteFile(path, []byte("content"), 0600))
+
+	u := upload.UploadFolderJob{
+		FileTracker: &mock.FileTracker{
+			IsUploadedFn:       func(string) bool { return true },
+			PendingMediaItemFn: func(string) (string, bool) { return "item-1", true },
+		},
+		SourceFolder: dir,
+		Filter:       filter.MustCompile([]string{"_ALL_FILES_"}, nil),
+	}
+
+	got, err := u.ExplainFile(path)
+	require.NoError(t, err)
+
+	step, skipped := got.SkippedBy()
+	assert.False(t, skipped, "skipped by %s: %s", step.Name, step.Result)
+	assert.True(t, got.Uploaded)
+	assert.Equal(t, "item-1", got.PendingMediaItemID)
+	assert.Contains(t, got.Steps, upload.ExplanationStep{Name: "File tracker", Result: "uploaded, pending extra albums (media item item-1)"})
+}
+
+// readOnlyRepository is a file tracker repository with a single tracked file. It fails the test if it's updated.
+type readOnlyRepository struct {
+	t    *testing.T
+	item filetracker.TrackedFile
+}
+
+func (r *readOnlyRepository) Get(string) (filetracker.TrackedFile, bool) {
+	return r.item, true
+}
+
+func (r *readOnlyRepository) Put(key string, _ filetracker.TrackedFile) error {
+	r.t.Errorf("unexpected update of '%s'", key)
+	return nil
+}
+
+func (r *readOnlyRepository) Delete(key string) error {
+	r.t.Errorf("unexpected deletion of '%s'", key)
+	return nil
+}
+
+func (r *readOnlyRepository) Close() error {
+	return nil
+}
+
+func (r *readOnlyRepository) Destroy() error {
+	return nil
+}
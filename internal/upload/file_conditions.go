@@ -0,0 +1,86 @@
+package upload
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileConditions are the conditions that files must meet to be uploaded, besides the Filter patterns.
+// Zero values are not checked.
+type FileConditions struct {
+	// MinSize and MaxSize are the limits of the file size, in bytes.
+	MinSize int64
+	MaxSize int64
+
+	// NewerThan and OlderThan are the limits of the age of the files, using their modification time.
+	NewerThan time.Duration
+	OlderThan time.Duration
+
+	// CapturedFrom and CapturedBefore are the limits of the capture time of the files. CapturedFrom is inclusive and
+	// CapturedBefore is exclusive.
+	CapturedFrom   time.Time
+	CapturedBefore time.Time
+}
+
+// matchesFile returns true if the size and modification time of the file meet the conditions, at the given time.
+func (c FileConditions) matchesFile(size int64, modTime time.Time, now time.Time) bool {
+	return c.fileMismatch(size, modTime, now) == ""
+}
+
+// fileMismatch returns the condition that the size or the modification time of the file doesn't meet, at the given
+// time, or an empty string if it meets all of them.
+func (c FileConditions) fileMismatch(size int64, modTime time.Time, now time.Time) string {
+	if c.MinSize > 0 && size < c.MinSize {
+		return fmt.Sprintf("it's smaller than %d bytes", c.MinSize)
+	}
+	if c.MaxSize > 0 && size > c.MaxSize {
+		return fmt.Sprintf("it's bigger than %d bytes", c.MaxSize)
+	}
+	if c.NewerThan > 0 && modTime.Before(now.Add(-c.NewerThan)) {
+		return fmt.Sprintf("it's older than %s", c.NewerThan)
+	}
+	if c.OlderThan > 0 && modTime.After(now.Add(-c.OlderThan)) {
+		return fmt.Sprintf("it's newer than %s", c.OlderThan)
+	}
+	return ""
+}
+
+// matchesCaptureTime returns true if the capture time of the file meets the conditions.
+func (c FileConditions) matchesCaptureTime(captureTime time.Time) bool {
+	return c.captureTimeMismatch(captureTime) == ""
+}
+
+// captureTimeMismatch returns the condition that the capture time of the file doesn't meet, or an empty string if
+// it meets all of them.
+func (c FileConditions) captureTimeMismatch(captureTime time.Time) string {
+	if !c.CapturedFrom.IsZero() && captureTime.Before(c.CapturedFrom) {
+		return fmt.Sprintf("it was captured before %s", c.CapturedFrom.Format(time.DateTime))
+	}
+	if !c.CapturedBefore.IsZero() && !captureTime.Before(c.CapturedBefore) {
+		return fmt.Sprintf("it was captured on or after %s", c.CapturedBefore.Format(time.DateTime))
+	}
+	return ""
+}
+
+// ParseAge returns the duration of a value like "30d", "2w" or any value accepted by time.ParseDuration, like "36h".
+// Days and weeks are 24 and 168 hours long.
+func ParseAge(value string) (time.Duration, error) {
+	s := strings.TrimSpace(value)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if number, found := strings.CutSuffix(s, suffix); found {
+			n, err := strconv.ParseFloat(number, 64)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid age '%s'", value)
+			}
+			return time.Duration(n * float64(unit)), nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid age '%s'", value)
+	}
+	return d, nil
+}
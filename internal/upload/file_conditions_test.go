@@ -0,0 +1,91 @@
+package upload
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileConditions_MatchesFile(t *testing.T) {
+	now := time.Date(2024, time.May, 31, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	var testCases = []struct {
+		name       string
+		conditions FileConditions
+		size       int64
+		modTime    time.Time
+		want       bool
+	}{
+		{name: "NoConditions", size: 10, modTime: now, want: true},
+		{name: "SmallerThanMinSize", conditions: FileConditions{MinSize: 100}, size: 99, modTime: now, want: false},
+		{name: "EqualToMinSize", conditions: FileConditions{MinSize: 100}, size: 100, modTime: now, want: true},
+		{name: "BiggerThanMaxSize", conditions: FileConditions{MaxSize: 100}, size: 101, modTime: now, want: false},
+		{name: "EqualToMaxSize", conditions: FileConditions{MaxSize: 100}, size: 100, modTime: now, want: true},
+		{name: "NewerThan", conditions: FileConditions{NewerThan: 30 * day}, modTime: now.Add(-29 * day), want: true},
+		{name: "NotNewerThan", conditions: FileConditions{NewerThan: 30 * day}, modTime: now.Add(-31 * day), want: false},
+		{name: "OlderThan", conditions: FileConditions{OlderThan: 30 * day}, modTime: now.Add(-31 * day), want: true},
+		{name: "NotOlderThan", conditions: FileConditions{OlderThan: 30 * day}, modTime: now.Add(-29 * day), want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.conditions.matchesFile(tc.size, tc.modTime, now))
+		})
+	}
+}
+
+func TestFileConditions_MatchesCaptureTime(t *testing.T) {
+	conditions := FileConditions{
+		CapturedFrom:   time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC),
+		CapturedBefore: time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	var testCases = []struct {
+		name        string
+		captureTime time.Time
+		want        bool
+	}{
+		{name: "Before", captureTime: time.Date(2024, time.April, 30, 23, 59, 0, 0, time.UTC), want: false},
+		{name: "From", captureTime: time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC), want: true},
+		{name: "Within", captureTime: time.Date(2024, time.May, 31, 23, 59, 0, 0, time.UTC), want: true},
+		{name: "AtCapturedBefore", captureTime: time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC), want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, conditions.matchesCaptureTime(tc.captureTime))
+		})
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	var testCases = []struct {
+		value       string
+		want        time.Duration
+		errExpected bool
+	}{
+		{value: "30d", want: 30 * 24 * time.Hour},
+		{value: "2w", want: 14 * 24 * time.Hour},
+		{value: "1.5d", want: 36 * time.Hour},
+		{value: "36h", want: 36 * time.Hour},
+		{value: " 90m ", want: 90 * time.Minute},
+		{value: "0d", errExpected: true},
+		{value: "-1h", errExpected: true},
+		{value: "d", errExpected: true},
+		{value: "foo", errExpected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			got, err := ParseAge(tc.value)
+			if tc.errExpected {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package upload
 import (
 	"io"
 	"path"
+	"time"
 
 	"github.com/spf13/afero"
 )
@@ -17,6 +18,18 @@ var (
 type FileItem struct {
 	Path      string
 	AlbumName string
+	// AlbumID is the fixed album where the file is uploaded, if it has been mapped using the AlbumMap option.
+	AlbumID string
+	// ExtraAlbumNames are other albums where the file is added once it has been uploaded.
+	ExtraAlbumNames []string
+	// CaptureTime is the time when the photo or video was taken. It's the modification time if it's unknown.
+	CaptureTime time.Time
+	// PairedFiles are the siblings of the file skipped by the pairing policies, like the RAW file of a JPEG file.
+	// They are tracked as paired once the file is uploaded.
+	PairedFiles []string
+	// MediaItemID is the media item of a file already uploaded, but not added to all its extra albums yet. These
+	// files are only added to their extra albums.
+	MediaItemID string
 }
 
 // NewFileItem creates a new instance of FileItem.
@@ -66,12 +79,58 @@ func (f FileItem) Remove() error {
 	return appFS.Remove(f.Path)
 }
 
-// GroupByAlbum groups FileItem objects by their AlbumName.
-func GroupByAlbum(items []FileItem) map[string][]FileItem {
+// AlbumKey identifies the album of a FileItem: its AlbumID if it has been mapped to a fixed album, or its
+// AlbumName otherwise. Only one of them is set, so album IDs and names never collide.
+type AlbumKey struct {
+	ID   string
+	Name string
+}
+
+// albumKey returns the AlbumKey of the file.
+func (f FileItem) albumKey() AlbumKey {
+	if f.AlbumID != "" {
+		return AlbumKey{ID: f.AlbumID}
+	}
+	return AlbumKey{Name: f.AlbumName}
+}
+
+// GroupByAlbum groups FileItem objects by their AlbumID, or by their AlbumName if they have not been mapped to a fixed album.
+func GroupByAlbum(items []FileItem) map[AlbumKey][]FileItem {
+	groups := make(map[AlbumKey][]FileItem)
+
+	for _, item := range items {
+		key := item.albumKey()
+		groups[key] = append(groups[key], item)
+	}
+
+	return groups
+}
+
+// AlbumKeys returns the keys of the GroupByAlbum groups in the order their first item appears in items.
+func AlbumKeys(items []FileItem) []AlbumKey {
+	var keys []AlbumKey
+	seen := make(map[AlbumKey]bool)
+
+	for _, item := range items {
+		key := item.albumKey()
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// GroupByExtraAlbum groups FileItem objects by their ExtraAlbumNames.
+// An item is added to as many groups as extra albums it has.
+func GroupByExtraAlbum(items []FileItem) map[string][]FileItem {
 	groups := make(map[string][]FileItem)
 
 	for _, item := range items {
-		groups[item.AlbumName] = append(groups[item.AlbumName], item)
+		for _, albumName := range item.ExtraAlbumNames {
+			groups[albumName] = append(groups[albumName], item)
+		}
 	}
 
 	return groups
@@ -138,16 +138,16 @@ func TestFileItem_GroupByAlbum(t *testing.T) {
 		{Path: "file5.jpg", AlbumName: "album 3"},
 	}
 
-	expectedGroups := map[string][]FileItem{
-		"album 1": {
+	expectedGroups := map[AlbumKey][]FileItem{
+		{Name: "album 1"}: {
 			{Path: "file1.jpg", AlbumName: "album 1"},
 			{Path: "file3.jpg", AlbumName: "album 1"},
 		},
-		"album 2": {
+		{Name: "album 2"}: {
 			{Path: "file2.jpg", AlbumName: "album 2"},
 			{Path: "file4.jpg", AlbumName: "album 2"},
 		},
-		"album 3": {
+		{Name: "album 3"}: {
 			{Path: "file5.jpg", AlbumName: "album 3"},
 		},
 	}
@@ -156,9 +156,63 @@ func TestFileItem_GroupByAlbum(t *testing.T) {
 
 	assert.Len(t, groupedItems, len(expectedGroups))
 
-	for albumName, expectedItems := range expectedGroups {
+	for key, expectedItems := range expectedGroups {
 
-		assert.Contains(t, groupedItems, albumName)
-		assert.Equal(t, expectedItems, groupedItems[albumName])
+		assert.Contains(t, groupedItems, key)
+		assert.Equal(t, expectedItems, groupedItems[key])
 	}
 }
+
+func TestFileItem_GroupByAlbumWithAlbumID(t *testing.T) {
+	items := []FileItem{
+		{Path: "file1.jpg", AlbumName: "album 1", AlbumID: "id-1"},
+		{Path: "file2.jpg", AlbumName: "album 1", AlbumID: "id-2"},
+		{Path: "file3.jpg", AlbumName: "album 1"},
+	}
+
+	groupedItems := GroupByAlbum(items)
+
+	assert.Len(t, groupedItems, 3)
+	assert.Equal(t, []FileItem{items[0]}, groupedItems[AlbumKey{ID: "id-1"}])
+	assert.Equal(t, []FileItem{items[1]}, groupedItems[AlbumKey{ID: "id-2"}])
+	assert.Equal(t, []FileItem{items[2]}, groupedItems[AlbumKey{Name: "album 1"}])
+}
+
+func TestFileItem_GroupByAlbumWithAlbumIDEqualToAName(t *testing.T) {
+	items := []FileItem{
+		{Path: "file1.jpg", AlbumName: "mapped", AlbumID: "album 1"},
+		{Path: "file2.jpg", AlbumName: "album 1"},
+	}
+
+	groupedItems := GroupByAlbum(items)
+
+	assert.Len(t, groupedItems, 2)
+	assert.Equal(t, []FileItem{items[0]}, groupedItems[AlbumKey{ID: "album 1"}])
+	assert.Equal(t, []FileItem{items[1]}, groupedItems[AlbumKey{Name: "album 1"}])
+	assert.Equal(t, []AlbumKey{{ID: "album 1"}, {Name: "album 1"}}, AlbumKeys(items))
+}
+
+func TestFileItem_GroupByExtraAlbum(t *testing.T) {
+	items := []FileItem{
+		{Path: "file1.jpg", AlbumName: "album 1", ExtraAlbumNames: []string{"album 2", "album 3"}},
+		{Path: "file2.jpg", AlbumName: "album 1", ExtraAlbumNames: []string{"album 2"}},
+		{Path: "file3.jpg", AlbumName: "album 1"},
+	}
+
+	groupedItems := GroupByExtraAlbum(items)
+
+	assert.Len(t, groupedItems, 2)
+	assert.Equal(t, []FileItem{items[0], items[1]}, groupedItems["album 2"])
+	assert.Equal(t, []FileItem{items[0]}, groupedItems["album 3"])
+}
+
+func TestAlbumKeys(t *testing.T) {
+	items := []FileItem{
+		{Path: "file1.jpg", AlbumName: "album 2"},
+		{Path: "file2.jpg", AlbumName: "album 1"},
+		{Path: "file3.jpg", AlbumName: "album 2"},
+		{Path: "file4.jpg", AlbumName: "album 1", AlbumID: "id-1"},
+	}
+
+	assert.Equal(t, []AlbumKey{{Name: "album 2"}, {Name: "album 1"}, {ID: "id-1"}}, AlbumKeys(items))
+}
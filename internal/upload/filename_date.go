@@ -0,0 +1,134 @@
+package upload
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// filenameDatePattern is a regular expression to find dates in file names.
+type filenameDatePattern struct {
+	re *regexp.Regexp
+	// location is the time zone of the dates found by the pattern.
+	location *time.Location
+}
+
+// builtinFilenameDatePatterns are the patterns for common phone and camera naming conventions.
+// The first one to match is used, so specific patterns go first.
+var builtinFilenameDatePatterns = []filenameDatePattern{
+	// Google Pixel, like "PXL_20230101_101500123.jpg". The time is in UTC.
+	{
+		re:       regexp.MustCompile(`^PXL_(?P<year>\d{4})(?P<month>\d{2})(?P<day>\d{2})_(?P<hour>\d{2})(?P<minute>\d{2})(?P<second>\d{2})\d{3}`),
+		location: time.UTC,
+	},
+	// WhatsApp, like "IMG-20210314-WA0001.jpg".
+	{
+		re: regexp.MustCompile(`^(?:IMG|VID|AUD|PTT|STK)-(?P<year>\d{4})(?P<month>\d{2})(?P<day>\d{2})-WA\d+`),
+	},
+	// Screenshots, like "Screenshot_2022-05-01-10-15-00.png", "Screenshot_20220501-101500.png",
+	// "Screenshot 2022-05-01 at 10.15.00.png" or "Screenshot from 2022-05-01 10-15-00.png".
+	{
+		re: regexp.MustCompile(`^Screenshot(?: from)?[_ -](?P<year>\d{4})-?(?P<month>\d{2})-?(?P<day>\d{2})(?:[-_ ]| at )(?P<hour>\d{2})[-.:]?(?P<minute>\d{2})[-.:]?(?P<second>\d{2})`),
+	},
+	// Any other date, like "IMG_20210314_101500.jpg", "VID_20210314_101500.mp4" or "2021-03-14 10.15.00.jpg".
+	// The time is optional.
+	{
+		re: regexp.MustCompile(`(?:^|\D)(?P<year>(?:19|20)\d{2})[-_.]?(?P<month>0[1-9]|1[0-2])[-_.]?(?P<day>0[1-9]|[12]\d|3[01])(?:[-_ T.]?(?P<hour>[01]\d|2[0-3])[-_.:]?(?P<minute>[0-5]\d)[-_.:]?(?P<second>[0-5]\d))?(?:\D|$)`),
+	},
+}
+
+// defaultFilenameDateParser uses the built-in patterns only.
+var defaultFilenameDateParser = &FilenameDateParser{patterns: builtinFilenameDatePatterns}
+
+// FilenameDateParser finds dates in file names, like "IMG_20210314_101500.jpg".
+type FilenameDateParser struct {
+	patterns []filenameDatePattern
+}
+
+// NewFilenameDateParser returns a FilenameDateParser that uses the given patterns before the built-in ones.
+// Patterns are regular expressions with the named groups "year", "month" and "day", and optionally "hour", "minute"
+// and "second". Dates use the local time zone.
+func NewFilenameDateParser(patterns []string) (*FilenameDateParser, error) {
+	p := &FilenameDateParser{}
+	for _, pattern := range patterns {
+		re, err := compileFilenameDatePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		p.patterns = append(p.patterns, filenameDatePattern{re: re})
+	}
+	p.patterns = append(p.patterns, builtinFilenameDatePatterns...)
+	return p, nil
+}
+
+// ValidateFilenameDatePattern validates the given filename date pattern.
+func ValidateFilenameDatePattern(pattern string) error {
+	_, err := compileFilenameDatePattern(pattern)
+	return err
+}
+
+func compileFilenameDatePattern(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filename date pattern '%s': %w", pattern, err)
+	}
+
+	groups := make(map[string]bool)
+	for _, name := range re.SubexpNames() {
+		groups[name] = true
+	}
+	for _, name := range []string{"year", "month", "day"} {
+		if !groups[name] {
+			return nil, fmt.Errorf("invalid filename date pattern '%s': missing '%s' named group", pattern, name)
+		}
+	}
+	return re, nil
+}
+
+// Parse returns the date found in the file name by the first matching pattern.
+func (p *FilenameDateParser) Parse(name string) (time.Time, bool) {
+	for _, pattern := range p.patterns {
+		if t, ok := pattern.parse(name); ok {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parse returns the date found in the file name, if it's a valid date.
+func (p filenameDatePattern) parse(name string) (time.Time, bool) {
+	match := p.re.FindStringSubmatch(name)
+	if match == nil {
+		return time.Time{}, false
+	}
+
+	values := make(map[string]int)
+	for i, group := range p.re.SubexpNames() {
+		if group == "" || match[i] == "" {
+			continue
+		}
+		v, err := strconv.Atoi(match[i])
+		if err != nil {
+			return time.Time{}, false
+		}
+		values[group] = v
+	}
+
+	year, month, day := values["year"], values["month"], values["day"]
+	hour, minute, second := values["hour"], values["minute"], values["second"]
+	if month < 1 || month > 12 || hour > 23 || minute > 59 || second > 59 {
+		return time.Time{}, false
+	}
+
+	location := p.location
+	if location == nil {
+		location = time.Local
+	}
+	t := time.Date(year, time.Month(month), day, hour, minute, second, 0, location)
+	// Invalid dates, like February 30th, are normalized by time.Date.
+	if t.Day() != day {
+		return time.Time{}, false
+	}
+	return t.In(time.Local), true
+}
@@ -0,0 +1,74 @@
+package upload
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilenameDateParser_Parse(t *testing.T) {
+	var testCases = []struct {
+		in     string
+		want   time.Time
+		wantOk bool
+	}{
+		{in: "IMG_20210314_101500.jpg", want: time.Date(2021, time.March, 14, 10, 15, 0, 0, time.Local), wantOk: true},
+		{in: "VID_20210314_101500.mp4", want: time.Date(2021, time.March, 14, 10, 15, 0, 0, time.Local), wantOk: true},
+		{in: "IMG-20210314-WA0001.jpg", want: time.Date(2021, time.March, 14, 0, 0, 0, 0, time.Local), wantOk: true},
+		{in: "PXL_20230101_101500123.jpg", want: time.Date(2023, time.January, 1, 10, 15, 0, 0, time.UTC).Local(), wantOk: true},
+		{in: "PXL_20230101_101500123.MP.jpg", want: time.Date(2023, time.January, 1, 10, 15, 0, 0, time.UTC).Local(), wantOk: true},
+		{in: "Screenshot_2022-05-01-10-15-00.png", want: time.Date(2022, time.May, 1, 10, 15, 0, 0, time.Local), wantOk: true},
+		{in: "Screenshot_20220501-101500_Chrome.jpg", want: time.Date(2022, time.May, 1, 10, 15, 0, 0, time.Local), wantOk: true},
+		{in: "Screenshot 2022-05-01 at 10.15.00.png", want: time.Date(2022, time.May, 1, 10, 15, 0, 0, time.Local), wantOk: true},
+		{in: "Screenshot from 2022-05-01 10-15-00.png", want: time.Date(2022, time.May, 1, 10, 15, 0, 0, time.Local), wantOk: true},
+		{in: "2022-05-01 18.30.05.png", want: time.Date(2022, time.May, 1, 18, 30, 5, 0, time.Local), wantOk: true},
+		{in: "VID_20190230_101500.mp4", wantOk: false},
+		{in: "DSC_1234.jpg", wantOk: false},
+		{in: "123202103141.jpg", wantOk: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, ok := defaultFilenameDateParser.Parse(tc.in)
+
+			assert.Equal(t, tc.wantOk, ok)
+			assert.True(t, tc.want.Equal(got), "want %s, got %s", tc.want, got)
+		})
+	}
+}
+
+func TestFilenameDateParser_ParseWithUserPatterns(t *testing.T) {
+	p, err := NewFilenameDateParser([]string{`^trip-(?P<day>\d{2})\.(?P<month>\d{2})\.(?P<year>\d{4})`})
+	require.NoError(t, err)
+
+	got, ok := p.Parse("trip-14.03.2021-beach.jpg")
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2021, time.March, 14, 0, 0, 0, 0, time.Local), got)
+
+	// Built-in patterns are used if user patterns don't match.
+	got, ok = p.Parse("IMG-20210314-WA0001.jpg")
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2021, time.March, 14, 0, 0, 0, 0, time.Local), got)
+}
+
+func TestValidateFilenameDatePattern(t *testing.T) {
+	var testCases = []struct {
+		in      string
+		wantErr bool
+	}{
+		{in: `(?P<year>\d{4})(?P<month>\d{2})(?P<day>\d{2})`, wantErr: false},
+		{in: `(?P<year>\d{4})(?P<month>\d{2})(?P<day>\d{2})_(?P<hour>\d{2})(?P<minute>\d{2})`, wantErr: false},
+		{in: `(?P<year>\d{4})(?P<month>\d{2})`, wantErr: true},
+		{in: `(\d{4})(\d{2})(\d{2})`, wantErr: true},
+		{in: `(?P<year>\d{4}`, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			err := ValidateFilenameDatePattern(tc.in)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
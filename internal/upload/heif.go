@@ -0,0 +1,206 @@
+package upload
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+const (
+	// maxMetaBoxSize avoids reading corrupted or unexpectedly large 'meta' boxes.
+	maxMetaBoxSize = 4 << 20
+	// maxExifItemSize avoids reading corrupted or unexpectedly large EXIF items.
+	maxExifItemSize = 1 << 20
+)
+
+// isHEIFHeader returns true if the header belongs to a HEIF file, like HEIC or AVIF images.
+func isHEIFHeader(header []byte) bool {
+	if string(header[4:8]) != "ftyp" {
+		return false
+	}
+	switch string(header[8:12]) {
+	case "heic", "heix", "heim", "heis", "hevc", "hevx", "mif1", "msf1", "avif", "avis":
+		return true
+	}
+	return false
+}
+
+// heifMetadata returns the metadata stored in the EXIF item of a HEIF file.
+func heifMetadata(r io.ReaderAt) (fileMetadata, error) {
+	boxes, err := readBoxes(r, 0, -1)
+	if err != nil {
+		return fileMetadata{}, err
+	}
+	meta, ok := findBox(boxes, "meta")
+	if !ok || meta.size < 4 || meta.size > maxMetaBoxSize {
+		return fileMetadata{}, errNoMetadata
+	}
+
+	payload := make([]byte, meta.size)
+	if _, err := r.ReadAt(payload, meta.offset); err != nil {
+		return fileMetadata{}, err
+	}
+
+	// 'meta' is a full box, its children start after version and flags.
+	metaReader := bytes.NewReader(payload)
+	children, err := readBoxes(metaReader, 4, int64(len(payload)))
+	if err != nil {
+		// Corrupted boxes are handled as missing metadata.
+		return fileMetadata{}, errNoMetadata
+	}
+
+	iinf, ok := findBox(children, "iinf")
+	if !ok {
+		return fileMetadata{}, errNoMetadata
+	}
+	iinfPayload, ok := boxPayload(payload, iinf)
+	if !ok {
+		return fileMetadata{}, errNoMetadata
+	}
+	itemID, ok := exifItemID(iinfPayload)
+	if !ok {
+		return fileMetadata{}, errNoMetadata
+	}
+
+	iloc, ok := findBox(children, "iloc")
+	if !ok {
+		return fileMetadata{}, errNoMetadata
+	}
+	ilocPayload, ok := boxPayload(payload, iloc)
+	if !ok {
+		return fileMetadata{}, errNoMetadata
+	}
+	offset, length, ok := itemLocation(ilocPayload, itemID)
+	if !ok || length < 4 || length > maxExifItemSize {
+		return fileMetadata{}, errNoMetadata
+	}
+
+	item := make([]byte, length)
+	if _, err := r.ReadAt(item, int64(offset)); err != nil {
+		return fileMetadata{}, err
+	}
+
+	// The EXIF item starts with the offset to the TIFF header, usually skipping "Exif\0\0".
+	start := 4 + uint64(binary.BigEndian.Uint32(item))
+	if start >= length {
+		return fileMetadata{}, errNoMetadata
+	}
+	return tiffMetadata(bytes.NewReader(item[start:]))
+}
+
+// exifItemID returns the ID of the EXIF item from the payload of the 'iinf' box.
+func exifItemID(iinf []byte) (uint32, bool) {
+	if len(iinf) < 6 {
+		return 0, false
+	}
+	start := int64(6)
+	if iinf[0] > 0 {
+		start = 8
+	}
+
+	entries, err := readBoxes(bytes.NewReader(iinf), start, int64(len(iinf)))
+	if err != nil {
+		return 0, false
+	}
+
+	for _, entry := range entries {
+		if entry.typ != "infe" {
+			continue
+		}
+		infe, ok := boxPayload(iinf, entry)
+		if !ok {
+			return 0, false
+		}
+		// Item types are only available from version 2.
+		switch {
+		case len(infe) >= 12 && infe[0] == 2:
+			if string(infe[8:12]) == "Exif" {
+				return uint32(binary.BigEndian.Uint16(infe[4:])), true
+			}
+		case len(infe) >= 14 && infe[0] == 3:
+			if string(infe[10:14]) == "Exif" {
+				return binary.BigEndian.Uint32(infe[4:]), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// itemLocation returns the file offset and length of the first extent of the item from the payload of
+// the 'iloc' box. Only items stored in the file (construction method 0) are supported.
+func itemLocation(iloc []byte, itemID uint32) (uint64, uint64, bool) {
+	if len(iloc) < 8 {
+		return 0, 0, false
+	}
+	version := iloc[0]
+	offsetSize := int(iloc[4] >> 4)
+	lengthSize := int(iloc[4] & 0x0F)
+	baseOffsetSize := int(iloc[5] >> 4)
+	indexSize := 0
+	if version == 1 || version == 2 {
+		indexSize = int(iloc[5] & 0x0F)
+	}
+
+	p := &byteParser{b: iloc, pos: 6}
+	var itemCount uint64
+	if version < 2 {
+		itemCount = p.uint(2)
+	} else {
+		itemCount = p.uint(4)
+	}
+
+	for i := uint64(0); i < itemCount && p.err == nil; i++ {
+		var id uint64
+		if version < 2 {
+			id = p.uint(2)
+		} else {
+			id = p.uint(4)
+		}
+		constructionMethod := uint64(0)
+		if version == 1 || version == 2 {
+			constructionMethod = p.uint(2) & 0x0F
+		}
+		p.uint(2) // data_reference_index
+		baseOffset := p.uint(baseOffsetSize)
+		extentCount := p.uint(2)
+
+		var offset, length uint64
+		for e := uint64(0); e < extentCount && p.err == nil; e++ {
+			p.uint(indexSize)
+			extentOffset := p.uint(offsetSize)
+			extentLength := p.uint(lengthSize)
+			if e == 0 {
+				offset, length = baseOffset+extentOffset, extentLength
+			}
+		}
+
+		if p.err == nil && uint32(id) == itemID && constructionMethod == 0 && extentCount > 0 {
+			return offset, length, true
+		}
+	}
+	return 0, 0, false
+}
+
+// byteParser reads big-endian integers of variable size.
+type byteParser struct {
+	b   []byte
+	pos int
+	err error
+}
+
+// uint reads an integer of the given size in bytes. Size 0 returns 0.
+func (p *byteParser) uint(size int) uint64 {
+	if p.err != nil {
+		return 0
+	}
+	if size > 8 || p.pos+size > len(p.b) {
+		p.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	var v uint64
+	for _, c := range p.b[p.pos : p.pos+size] {
+		v = v<<8 | uint64(c)
+	}
+	p.pos += size
+	return v
+}
@@ -0,0 +1,115 @@
+package upload
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadMetadata_HEIF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "image.heic")
+	require.NoError(t, os.WriteFile(path, buildHEIF("heic", buildTIFF(binary.BigEndian, "2021:03:14 10:15:00", "")), 0600))
+
+	metadata, err := readMetadata(path)
+	got := metadata.captureTime
+
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2021, time.March, 14, 10, 15, 0, 0, time.Local), got)
+}
+
+func TestReadMetadata_HEIFWithoutExif(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "image.heic")
+	require.NoError(t, os.WriteFile(path, buildHEIF("heic", nil), 0600))
+
+	_, err := readMetadata(path)
+
+	assert.ErrorIs(t, err, errNoMetadata)
+}
+
+func TestReadMetadata_HEIFWithCorruptedBoxes(t *testing.T) {
+	testCases := []struct {
+		name string
+		box  string
+		size uint32
+	}{
+		{name: "Should fail if 'iinf' exceeds 'meta'", box: "iinf", size: 0x7FFFFFFF},
+		{name: "Should fail if 'infe' exceeds 'iinf'", box: "infe", size: 0x1000},
+		{name: "Should fail if 'iloc' exceeds 'meta'", box: "iloc", size: 0x1000},
+		{name: "Should fail if 'iinf' is truncated", box: "iinf", size: 9},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := buildHEIF("heic", buildTIFF(binary.BigEndian, "2021:03:14 10:15:00", ""))
+			i := bytes.Index(b, []byte(tc.box))
+			require.GreaterOrEqual(t, i, 4)
+			binary.BigEndian.PutUint32(b[i-4:], tc.size)
+
+			path := filepath.Join(t.TempDir(), "image.heic")
+			require.NoError(t, os.WriteFile(path, b, 0600))
+
+			_, err := readMetadata(path)
+
+			assert.ErrorIs(t, err, errNoMetadata)
+		})
+	}
+}
+
+func TestIsHEIFHeader(t *testing.T) {
+	var testCases = []struct {
+		in   string
+		want bool
+	}{
+		{in: "\x00\x00\x00\x18ftypheic", want: true},
+		{in: "\x00\x00\x00\x18ftypmif1", want: true},
+		{in: "\x00\x00\x00\x18ftypavif", want: true},
+		{in: "\x00\x00\x00\x18ftypisom", want: false},
+		{in: "\x00\x00\x00\x18moovheic", want: false},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, isHEIFHeader([]byte(tc.in)), tc.in)
+	}
+}
+
+// buildHEIF returns a HEIF file with an EXIF item containing the TIFF structure. No item is added if tiff is nil.
+func buildHEIF(brand string, tiff []byte) []byte {
+	box := func(typ string, payload ...[]byte) []byte {
+		content := bytes.Join(payload, nil)
+		b := binary.BigEndian.AppendUint32(nil, uint32(8+len(content)))
+		return append(append(b, typ...), content...)
+	}
+	u16 := func(v uint16) []byte { return binary.BigEndian.AppendUint16(nil, v) }
+	u32 := func(v uint32) []byte { return binary.BigEndian.AppendUint32(nil, v) }
+
+	ftyp := box("ftyp", []byte(brand), u32(0), []byte("mif1"+brand))
+	if tiff == nil {
+		return append(ftyp, box("meta", u32(0), box("hdlr", u32(0), u32(0), []byte("pict")))...)
+	}
+
+	// EXIF item: offset to the TIFF header, "Exif\0\0" and the TIFF structure.
+	item := append(append(u32(6), "Exif\x00\x00"...), tiff...)
+
+	iinf := box("iinf", u32(0), u16(2),
+		box("infe", []byte{2, 0, 0, 0}, u16(1), u16(0), []byte("hvc1")),
+		box("infe", []byte{2, 0, 0, 0}, u16(2), u16(0), []byte("Exif")),
+	)
+	ilocSize := 8 + 4 + 2 + 2 + 2*(2+2+2+4+4)
+	metaSize := 8 + 4 + len(iinf) + ilocSize
+	itemOffset := uint32(len(ftyp) + metaSize + 8)
+
+	iloc := box("iloc", u32(0), []byte{0x44, 0x00}, u16(2),
+		u16(1), u16(0), u16(1), u32(itemOffset+uint32(len(item))), u32(1),
+		u16(2), u16(0), u16(1), u32(itemOffset), u32(uint32(len(item))),
+	)
+	meta := box("meta", u32(0), iinf, iloc)
+	mdat := box("mdat", item, []byte{0})
+
+	return bytes.Join([][]byte{ftyp, meta, mdat}, nil)
+}
@@ -0,0 +1,147 @@
+package upload
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v2"
+)
+
+// IgnoreFilename is the name of the files that exclude files of their folder and subfolders, using the gitignore
+// syntax. They are applied in addition to the Filter of the job.
+const IgnoreFilename = ".gphotosignore"
+
+// ignoreRule is a pattern of an ignore file.
+type ignoreRule struct {
+	pattern string
+	// negate re-includes the files excluded by previous rules, when the pattern starts with '!'.
+	negate bool
+	// dirOnly matches only folders, when the pattern ends with '/'.
+	dirOnly bool
+	// anchored matches the path relative to the folder of the ignore file, when the pattern contains a '/'.
+	// Otherwise, the pattern matches the name of the file at any level.
+	anchored bool
+}
+
+// readIgnoreFile returns the rules of the ignore file of the folder, if any.
+func (job *UploadFolderJob) readIgnoreFile(dir string) ([]ignoreRule, error) {
+	b, err := os.ReadFile(filepath.Join(dir, IgnoreFilename))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseIgnoreFile(b, job.CaseInsensitivePatterns)
+}
+
+// parseIgnoreFile returns the rules of an ignore file. Empty lines and comments, starting with '#', are skipped.
+// If caseInsensitive is true, patterns are lowercased.
+func parseIgnoreFile(b []byte, caseInsensitive bool) ([]ignoreRule, error) {
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule ignoreRule
+		switch {
+		case strings.HasPrefix(line, `\#`), strings.HasPrefix(line, `\!`):
+			line = line[1:]
+		case strings.HasPrefix(line, "!"):
+			rule.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			rule.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		if caseInsensitive {
+			line = strings.ToLower(line)
+		}
+		if _, err := doublestar.Match(line, "x"); err != nil {
+			return nil, fmt.Errorf("invalid pattern '%s': %w", line, err)
+		}
+
+		rule.pattern = line
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// matches returns true if the path, relative to the folder of the ignore file, matches the rule.
+func (r ignoreRule) matches(relativePath string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	name := relativePath
+	if !r.anchored {
+		name = path.Base(relativePath)
+	}
+	matched, _ := doublestar.Match(r.pattern, name)
+	return matched
+}
+
+// String returns the rule as it's written in the ignore file.
+func (r ignoreRule) String() string {
+	var b strings.Builder
+	if r.negate {
+		b.WriteString("!")
+	}
+	if r.anchored && !strings.Contains(r.pattern, "/") {
+		b.WriteString("/")
+	}
+	b.WriteString(r.pattern)
+	if r.dirOnly {
+		b.WriteString("/")
+	}
+	return b.String()
+}
+
+// isIgnored returns true if the file or folder is excluded by the ignore files of its parent folders. Rules of the
+// nearest ignore files take precedence, and the last matching rule of a file wins.
+func (job *UploadFolderJob) isIgnored(relativePath string, isDir bool) bool {
+	_, rule, found := job.matchingIgnoreRule(relativePath, isDir)
+	return found && !rule.negate
+}
+
+// matchingIgnoreRule returns the rule deciding if the file or folder is ignored, and the folder of its ignore file.
+func (job *UploadFolderJob) matchingIgnoreRule(relativePath string, isDir bool) (string, ignoreRule, bool) {
+	if len(job.ignoreRules) == 0 {
+		return "", ignoreRule{}, false
+	}
+	for dir := filepath.Dir(relativePath); ; dir = filepath.Dir(dir) {
+		if rules := job.ignoreRules[dir]; len(rules) > 0 {
+			rel := relativePath
+			if dir != "." {
+				rel = RelativePath(dir, relativePath)
+			}
+			rel = filepath.ToSlash(rel)
+			if job.CaseInsensitivePatterns {
+				rel = strings.ToLower(rel)
+			}
+			for i := len(rules) - 1; i >= 0; i-- {
+				if rules[i].matches(rel, isDir) {
+					return dir, rules[i], true
+				}
+			}
+		}
+		if dir == "." || dir == string(filepath.Separator) {
+			return "", ignoreRule{}, false
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package upload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIgnoreFile(t *testing.T) {
+	content := `# Comment
+
+*.tmp
+!keep.tmp
+/Drafts/
+raw/**/*.xmp
+\#hash.jpg
+\!bang.jpg
+/
+`
+	want := []ignoreRule{
+		{pattern: "*.tmp"},
+		{pattern: "keep.tmp", negate: true},
+		{pattern: "Drafts", dirOnly: true, anchored: true},
+		{pattern: "raw/**/*.xmp", anchored: true},
+		{pattern: "#hash.jpg"},
+		{pattern: "!bang.jpg"},
+	}
+
+	got, err := parseIgnoreFile([]byte(content), false)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	_, err = parseIgnoreFile([]byte("[]a]"), false)
+	assert.Error(t, err)
+}
+
+func TestUploadFolderJob_IsIgnored(t *testing.T) {
+	job := UploadFolderJob{
+		ignoreRules: map[string][]ignoreRule{
+			".": {
+				{pattern: "*.tmp"},
+				{pattern: "Drafts", dirOnly: true, anchored: true},
+				{pattern: "cache", dirOnly: true},
+			},
+			"trip": {
+				{pattern: "keep.tmp", negate: true},
+				{pattern: "day1/*.jpg", anchored: true},
+			},
+		},
+	}
+
+	var testCases = []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "a.jpg", want: false},
+		{path: "a.tmp", want: true},
+		{path: "other/a.tmp", want: true},
+		{path: "Drafts", isDir: true, want: true},
+		{path: "Drafts", isDir: false, want: false},
+		{path: "other/Drafts", isDir: true, want: false},
+		{path: "other/cache", isDir: true, want: true},
+		{path: "trip/keep.tmp", want: false},
+		{path: "trip/other.tmp", want: true},
+		{path: "trip/day1/a.jpg", want: true},
+		{path: "trip/day1/a.png", want: false},
+		{path: "other/day1/a.jpg", want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			assert.Equal(t, tc.want, job.isIgnored(tc.path, tc.isDir))
+		})
+	}
+
+	t.Run("CaseInsensitivePatterns", func(t *testing.T) {
+		rules, err := parseIgnoreFile([]byte("*.TMP\n"), true)
+		require.NoError(t, err)
+
+		job := UploadFolderJob{CaseInsensitivePatterns: true, ignoreRules: map[string][]ignoreRule{".": rules}}
+		assert.True(t, job.isIgnored("a.Tmp", false))
+	})
+}
@@ -0,0 +1,133 @@
+package upload
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// quickTimeEpoch is the origin of the times stored in QuickTime and ISO-BMFF files.
+var quickTimeEpoch = time.Date(1904, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// isQuickTimeHeader returns true if the header belongs to a QuickTime or ISO-BMFF video, like MP4, MOV or 3GP.
+// Old QuickTime files don't start with the 'ftyp' box.
+func isQuickTimeHeader(header []byte) bool {
+	switch string(header[4:8]) {
+	case "ftyp", "moov", "mdat", "wide", "free", "skip", "pnot":
+		return true
+	}
+	return false
+}
+
+// quickTimeMetadata returns the creation time stored in the movie header ('mvhd') of a QuickTime or
+// ISO-BMFF video. Times are stored in UTC and returned in the local time zone.
+func quickTimeMetadata(r io.ReaderAt) (fileMetadata, error) {
+	boxes, err := readBoxes(r, 0, -1)
+	if err != nil {
+		return fileMetadata{}, err
+	}
+	moov, ok := findBox(boxes, "moov")
+	if !ok || moov.size < 0 {
+		return fileMetadata{}, errNoMetadata
+	}
+
+	children, err := readBoxes(r, moov.offset, moov.offset+moov.size)
+	if err != nil {
+		return fileMetadata{}, err
+	}
+	mvhd, ok := findBox(children, "mvhd")
+	if !ok || mvhd.size < 12 {
+		return fileMetadata{}, errNoMetadata
+	}
+
+	b := make([]byte, 12)
+	if _, err := r.ReadAt(b, mvhd.offset); err != nil {
+		return fileMetadata{}, err
+	}
+
+	var seconds uint64
+	switch b[0] {
+	case 0:
+		seconds = uint64(binary.BigEndian.Uint32(b[4:]))
+	case 1:
+		seconds = binary.BigEndian.Uint64(b[4:])
+	default:
+		return fileMetadata{}, fmt.Errorf("invalid 'mvhd' version: %d", b[0])
+	}
+
+	// Some encoders don't set the creation time, or set it to the Unix epoch.
+	unix := quickTimeEpoch.Unix() + int64(seconds)
+	if unix <= 0 {
+		return fileMetadata{}, errNoMetadata
+	}
+	return fileMetadata{captureTime: time.Unix(unix, 0).Local()}, nil
+}
+
+// isoBox is a box of the ISO Base Media File Format (ISO/IEC 14496-12).
+type isoBox struct {
+	typ string
+	// offset is the position of the box payload.
+	offset int64
+	size   int64
+}
+
+// readBoxes returns the boxes found between start and end. If end is negative, boxes are read until EOF.
+func readBoxes(r io.ReaderAt, start int64, end int64) ([]isoBox, error) {
+	var boxes []isoBox
+	header := make([]byte, 16)
+	for offset := start; end < 0 || offset+8 <= end; {
+		if _, err := r.ReadAt(header[:8], offset); err != nil {
+			if err == io.EOF {
+				return boxes, nil
+			}
+			return nil, err
+		}
+
+		size := int64(binary.BigEndian.Uint32(header))
+		headerSize := int64(8)
+		switch size {
+		case 0:
+			// The box extends to the end of the file.
+			if end < 0 {
+				return append(boxes, isoBox{typ: string(header[4:8]), offset: offset + headerSize, size: -1}), nil
+			}
+			size = end - offset
+		case 1:
+			if _, err := r.ReadAt(header[8:16], offset+8); err != nil {
+				return nil, err
+			}
+			size = int64(binary.BigEndian.Uint64(header[8:]))
+			headerSize = 16
+		}
+		if size < headerSize {
+			return nil, fmt.Errorf("invalid box size: %d", size)
+		}
+		// Boxes can't extend beyond their parent.
+		if end >= 0 && size > end-offset {
+			return nil, fmt.Errorf("invalid box size: %d", size)
+		}
+
+		boxes = append(boxes, isoBox{typ: string(header[4:8]), offset: offset + headerSize, size: size - headerSize})
+		offset += size
+	}
+	return boxes, nil
+}
+
+// boxPayload returns the payload of the box read from b, if it's within b.
+func boxPayload(b []byte, box isoBox) ([]byte, bool) {
+	if box.offset < 0 || box.size < 0 || box.offset > int64(len(b)) || box.size > int64(len(b))-box.offset {
+		return nil, false
+	}
+	return b[box.offset : box.offset+box.size], true
+}
+
+// findBox returns the first box of the given type.
+func findBox(boxes []isoBox, typ string) (isoBox, bool) {
+	for _, box := range boxes {
+		if box.typ == typ {
+			return box, true
+		}
+	}
+	return isoBox{}, false
+}
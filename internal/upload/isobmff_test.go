@@ -0,0 +1,83 @@
+package upload
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadMetadata_QuickTime(t *testing.T) {
+	want := time.Date(2021, time.March, 14, 10, 15, 0, 0, time.UTC)
+	seconds := uint64(want.Unix() - quickTimeEpoch.Unix())
+
+	var testCases = []struct {
+		name string
+		in   []byte
+	}{
+		{name: "MP4", in: buildQuickTime("isom", 0, seconds)},
+		{name: "MOVWithVersion1", in: buildQuickTime("qt  ", 1, seconds)},
+		{name: "MOVWithoutFtyp", in: buildQuickTime("", 0, seconds)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "video")
+			require.NoError(t, os.WriteFile(path, tc.in, 0600))
+
+			metadata, err := readMetadata(path)
+			got := metadata.captureTime
+
+			require.NoError(t, err)
+			assert.True(t, want.Equal(got), "want %s, got %s", want, got)
+		})
+	}
+}
+
+func TestReadMetadata_QuickTimeWithoutCreationTime(t *testing.T) {
+	var testCases = []string{
+		// Its creation time is the Unix epoch.
+		"testdata/SampleVideo.mp4",
+	}
+
+	path := filepath.Join(t.TempDir(), "video.mp4")
+	require.NoError(t, os.WriteFile(path, buildQuickTime("isom", 0, 0), 0600))
+	testCases = append(testCases, path)
+
+	for _, path := range testCases {
+		_, err := readMetadata(path)
+		assert.ErrorIs(t, err, errNoMetadata, path)
+	}
+}
+
+// buildQuickTime returns a video with a movie header with the given creation time. The 'ftyp' box is not added if
+// the brand is empty.
+func buildQuickTime(brand string, version byte, creationTime uint64) []byte {
+	box := func(typ string, payload ...[]byte) []byte {
+		content := bytes.Join(payload, nil)
+		b := binary.BigEndian.AppendUint32(nil, uint32(8+len(content)))
+		return append(append(b, typ...), content...)
+	}
+
+	var times []byte
+	if version == 0 {
+		times = binary.BigEndian.AppendUint32(nil, uint32(creationTime))
+		times = binary.BigEndian.AppendUint32(times, uint32(creationTime))
+	} else {
+		times = binary.BigEndian.AppendUint64(nil, creationTime)
+		times = binary.BigEndian.AppendUint64(times, creationTime)
+	}
+	mvhd := box("mvhd", []byte{version, 0, 0, 0}, times, make([]byte, 80))
+
+	var b []byte
+	if brand != "" {
+		b = box("ftyp", []byte(brand), make([]byte, 4))
+	}
+	b = append(b, box("mdat", make([]byte, 64))...)
+	return append(b, box("moov", mvhd, box("trak"))...)
+}
@@ -0,0 +1,143 @@
+package upload
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// These are the sources of the capture time of a file, used by the DateSources option.
+const (
+	// MetadataDateSource reads the capture time from the file metadata, like EXIF or video container metadata.
+	MetadataDateSource = "metadata"
+	// FilenameDateSource reads the capture time from the file name, like "IMG_20210314_101500.jpg".
+	FilenameDateSource = "filename"
+	// ModTimeDateSource uses the modification time of the file.
+	ModTimeDateSource = "mtime"
+)
+
+// DefaultDateSources are the sources of the capture time used when the DateSources option is not set.
+var DefaultDateSources = []string{MetadataDateSource, FilenameDateSource, ModTimeDateSource}
+
+// errNoMetadata is returned when a file doesn't contain metadata.
+var errNoMetadata = errors.New("metadata not found")
+
+// fileMetadata is the metadata of a file used to sort the files and calculate their album names.
+// Unknown values are empty.
+type fileMetadata struct {
+	captureTime time.Time
+	cameraMake  string
+	cameraModel string
+}
+
+// readFileMetadata returns the metadata of the file. The capture time is calculated using the DateSources option.
+// The file is only read when its metadata is used, see needsMetadata.
+func (job *UploadFolderJob) readFileMetadata(path string, modTime time.Time) fileMetadata {
+	var metadata fileMetadata
+	if job.needsMetadata() {
+		metadata, _ = readMetadata(path)
+	}
+	metadata.captureTime = job.captureTime(path, modTime, metadata.captureTime)
+	return metadata
+}
+
+// needsMetadata returns true if the metadata of the files is used: the camera by the album rules or templates, or
+// the capture time by the capture date conditions, the events, the ItemsOrder option or the templates, when it's
+// read from the metadata.
+func (job *UploadFolderJob) needsMetadata() bool {
+	usesCamera, usesCaptureTime := false, false
+	for _, rule := range job.AlbumRules {
+		if rule.CameraModel != "" {
+			usesCamera = true
+		}
+	}
+	for _, option := range job.albumOptions() {
+		if template, found := strings.CutPrefix(option, "template:"); found {
+			if t, err := job.albumTemplate(template); err == nil {
+				usesCamera = usesCamera || t.usesCamera()
+				usesCaptureTime = usesCaptureTime || t.usesCaptureTime()
+			}
+		}
+	}
+	if usesCamera {
+		return true
+	}
+
+	usesCaptureTime = usesCaptureTime || job.sortsItems() || job.mayUseEvents() ||
+		!job.Conditions.CapturedFrom.IsZero() || !job.Conditions.CapturedBefore.IsZero()
+	sources := job.DateSources
+	if len(sources) == 0 {
+		sources = DefaultDateSources
+	}
+	return usesCaptureTime && slices.Contains(sources, MetadataDateSource)
+}
+
+// captureTime returns the time when the file was taken, using the first DateSources that knows it.
+// The modification time is used if none of them knows it. metadataTime is the capture time read from the file
+// metadata, or zero if it's unknown.
+func (job *UploadFolderJob) captureTime(path string, modTime time.Time, metadataTime time.Time) time.Time {
+	sources := job.DateSources
+	if len(sources) == 0 {
+		sources = DefaultDateSources
+	}
+
+	for _, source := range sources {
+		switch source {
+		case MetadataDateSource:
+			if !metadataTime.IsZero() {
+				return metadataTime
+			}
+		case FilenameDateSource:
+			if t, ok := job.filenameDateParser().Parse(filepath.Base(path)); ok {
+				return t
+			}
+		case ModTimeDateSource:
+			return modTime
+		}
+	}
+
+	return modTime
+}
+
+// readMetadata returns the metadata stored in the file.
+// Supported formats are JPEG, TIFF-based RAW and HEIF (HEIC, AVIF) images, and QuickTime, ISO-BMFF (MP4, MOV, 3GP)
+// and AVCHD (MTS, M2TS) videos.
+func readMetadata(path string) (fileMetadata, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return fileMetadata{}, err
+	}
+	defer f.Close() //nolint:errcheck
+
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(f, header); err != nil {
+		return fileMetadata{}, errNoMetadata
+	}
+
+	switch {
+	case isJPEGHeader(header):
+		return jpegMetadata(f)
+	case isTIFFHeader(header):
+		return tiffMetadata(f)
+	case isHEIFHeader(header):
+		return heifMetadata(f)
+	case isQuickTimeHeader(header):
+		return quickTimeMetadata(f)
+	case isMTSHeader(header):
+		return mtsMetadata(f)
+	}
+
+	return fileMetadata{}, errNoMetadata
+}
+
+// filenameDateParser returns the parser of dates in file names, using the built-in patterns if it's not set.
+func (job *UploadFolderJob) filenameDateParser() *FilenameDateParser {
+	if job.FilenameDateParser == nil {
+		return defaultFilenameDateParser
+	}
+	return job.FilenameDateParser
+}
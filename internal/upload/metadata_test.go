@@ -0,0 +1,71 @@
+package upload
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadFolderJob_ReadFileMetadata(t *testing.T) {
+	modTime := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.Local)
+	exifTime := time.Date(2017, time.September, 11, 22, 15, 46, 0, time.Local)
+	filenameTime := time.Date(2021, time.March, 14, 10, 15, 0, 0, time.Local)
+
+	dir := t.TempDir()
+	withExif := filepath.Join(dir, "IMG_20210314_101500.jpg")
+	b, err := os.ReadFile("testdata/SampleJPGImage.jpg")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(withExif, b, 0600))
+	withoutExif := filepath.Join(dir, "IMG_20210314_101500.png")
+	require.NoError(t, os.WriteFile(withoutExif, []byte("not an image"), 0600))
+
+	var testCases = []struct {
+		name    string
+		sources []string
+		path    string
+		want    time.Time
+	}{
+		{name: "DefaultSourcesWithMetadata", path: withExif, want: exifTime},
+		{name: "DefaultSourcesWithoutMetadata", path: withoutExif, want: filenameTime},
+		{name: "FilenameFirst", sources: []string{FilenameDateSource, MetadataDateSource}, path: withExif, want: filenameTime},
+		{name: "ModTimeFirst", sources: []string{ModTimeDateSource, MetadataDateSource}, path: withExif, want: modTime},
+		{name: "ModTimeWhenNoSourceKnowsIt", sources: []string{MetadataDateSource}, path: withoutExif, want: modTime},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := UploadFolderJob{DateSources: tc.sources, ItemsOrder: OldestFirstItemsOrder}
+
+			assert.Equal(t, tc.want, job.readFileMetadata(tc.path, modTime).captureTime)
+		})
+	}
+}
+
+func TestUploadFolderJob_NeedsMetadata(t *testing.T) {
+	var testCases = []struct {
+		name string
+		job  UploadFolderJob
+		want bool
+	}{
+		{name: "ItemsOrder", job: UploadFolderJob{Album: "auto:folderName", ItemsOrder: OldestFirstItemsOrder}, want: true},
+		{name: "NoItemsOrder", job: UploadFolderJob{Album: "auto:folderName"}, want: false},
+		{name: "CaptureTimeTemplate", job: UploadFolderJob{Album: "template:%_directory% %_year%", ItemsOrder: NoItemsOrder}, want: true},
+		{name: "DateFunction", job: UploadFolderJob{Album: "template:$lower($date(MMMM))", ItemsOrder: NoItemsOrder}, want: true},
+		{name: "CaptureTimeNotReadFromMetadata", job: UploadFolderJob{Album: "template:%_year%", ItemsOrder: NoItemsOrder, DateSources: []string{FilenameDateSource, ModTimeDateSource}}, want: false},
+		{name: "CameraTemplate", job: UploadFolderJob{Albums: []string{"template:%_camera_model%"}, ItemsOrder: NoItemsOrder, DateSources: []string{ModTimeDateSource}}, want: true},
+		{name: "CameraRule", job: UploadFolderJob{AlbumRules: []AlbumRule{{CameraModel: "Pixel*", Album: "name:Pixel"}}, ItemsOrder: NoItemsOrder, DateSources: []string{ModTimeDateSource}}, want: true},
+		{name: "CaptureDateConditions", job: UploadFolderJob{ItemsOrder: NoItemsOrder, Conditions: FileConditions{CapturedFrom: time.Now()}}, want: true},
+		{name: "Events", job: UploadFolderJob{Album: EventAlbumOption, ItemsOrder: NoItemsOrder}, want: true},
+		{name: "AlbumOverride", job: UploadFolderJob{ItemsOrder: NoItemsOrder, albumOverrides: map[string]string{"trip": "template:%_year%"}}, want: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.job.needsMetadata())
+		})
+	}
+}
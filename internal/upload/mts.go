@@ -0,0 +1,110 @@
+package upload
+
+import (
+	"bytes"
+	"io"
+	"time"
+)
+
+const (
+	// tsPacketSize is the size of the MPEG transport stream packets.
+	tsPacketSize = 188
+	// m2tsPacketSize is the size of the BDAV MPEG-2 transport stream packets (M2TS, MTS), with a 4 bytes timestamp.
+	m2tsPacketSize = 192
+	// maxMDPMSearchSize is the size of the beginning of the stream where the AVCHD metadata is searched.
+	maxMDPMSearchSize = 2 << 20
+
+	mdpmTagDateTimeOriginal1 = 0x18
+	mdpmTagDateTimeOriginal2 = 0x19
+)
+
+// mdpmMarker identifies the AVCHD Modified Digital Video Pack Metadata (MDPM) in the H.264 SEI user data.
+var mdpmMarker = []byte("MDPM")
+
+// isMTSHeader returns true if the header belongs to an MPEG transport stream, like AVCHD MTS or M2TS videos.
+func isMTSHeader(header []byte) bool {
+	return header[0] == 0x47 || header[4] == 0x47
+}
+
+// mtsMetadata returns the recording time stored in the MDPM of an AVCHD video. The recording time zone is
+// not read, so it's returned in the local time zone, like the camera clock.
+func mtsMetadata(r io.ReaderAt) (fileMetadata, error) {
+	// Check the sync byte of the two first packets, to avoid scanning files that are not transport streams.
+	sync := make([]byte, m2tsPacketSize+5)
+	if _, err := r.ReadAt(sync, 0); err != nil {
+		return fileMetadata{}, errNoMetadata
+	}
+	if !(sync[0] == 0x47 && sync[tsPacketSize] == 0x47) && !(sync[4] == 0x47 && sync[m2tsPacketSize+4] == 0x47) {
+		return fileMetadata{}, errNoMetadata
+	}
+
+	buf := make([]byte, maxMDPMSearchSize)
+	n, err := r.ReadAt(buf, 0)
+	if err != nil && err != io.EOF {
+		return fileMetadata{}, err
+	}
+	buf = buf[:n]
+
+	for i := bytes.Index(buf, mdpmMarker); i >= 0; {
+		if t, ok := parseMDPM(buf[i+len(mdpmMarker):]); ok {
+			return fileMetadata{captureTime: t}, nil
+		}
+		next := bytes.Index(buf[i+1:], mdpmMarker)
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return fileMetadata{}, errNoMetadata
+}
+
+// parseMDPM returns the recording time from the MDPM tags. Each tag is one byte with the tag ID, followed by
+// four bytes with its value.
+func parseMDPM(b []byte) (time.Time, bool) {
+	if len(b) < 1 {
+		return time.Time{}, false
+	}
+	count := int(b[0])
+	b = b[1:]
+
+	var date, clock []byte
+	for i := 0; i < count && len(b) >= 5; i++ {
+		switch b[0] {
+		case mdpmTagDateTimeOriginal1:
+			// Time zone, year (two bytes) and month.
+			date = b[1:5]
+		case mdpmTagDateTimeOriginal2:
+			// Day, hours, minutes and seconds.
+			clock = b[1:5]
+		}
+		b = b[5:]
+	}
+	if date == nil || clock == nil {
+		return time.Time{}, false
+	}
+
+	values := make([]int, 0, 7)
+	for _, v := range append(append([]byte{}, date[1:]...), clock...) {
+		d, ok := bcd(v)
+		if !ok {
+			return time.Time{}, false
+		}
+		values = append(values, d)
+	}
+
+	year := values[0]*100 + values[1]
+	t := time.Date(year, time.Month(values[2]), values[3], values[4], values[5], values[6], 0, time.Local)
+	if year < 1970 || t.Month() != time.Month(values[2]) || t.Day() != values[3] {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// bcd decodes a Binary-Coded Decimal byte.
+func bcd(b byte) (int, bool) {
+	high, low := b>>4, b&0x0F
+	if high > 9 || low > 9 {
+		return 0, false
+	}
+	return int(high)*10 + int(low), true
+}
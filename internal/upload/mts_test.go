@@ -0,0 +1,75 @@
+package upload
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadMetadata_MTS(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		packetSize int
+		mdpm       []byte
+		want       time.Time
+		wantErr    bool
+	}{
+		{
+			name:       "M2TS",
+			packetSize: m2tsPacketSize,
+			mdpm:       []byte{2, 0x18, 0x00, 0x20, 0x21, 0x03, 0x19, 0x14, 0x10, 0x15, 0x00},
+			want:       time.Date(2021, time.March, 14, 10, 15, 0, 0, time.Local),
+		},
+		{
+			name:       "TS",
+			packetSize: tsPacketSize,
+			mdpm:       []byte{3, 0x13, 0, 0, 0, 0, 0x18, 0x00, 0x20, 0x21, 0x03, 0x19, 0x14, 0x10, 0x15, 0x00},
+			want:       time.Date(2021, time.March, 14, 10, 15, 0, 0, time.Local),
+		},
+		{
+			name:       "InvalidBCD",
+			packetSize: m2tsPacketSize,
+			mdpm:       []byte{2, 0x18, 0x00, 0x20, 0x2A, 0x03, 0x19, 0x14, 0x10, 0x15, 0x00},
+			wantErr:    true,
+		},
+		{
+			name:       "WithoutDate",
+			packetSize: m2tsPacketSize,
+			mdpm:       []byte{1, 0x13, 0, 0, 0, 0},
+			wantErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "video.mts")
+			require.NoError(t, os.WriteFile(path, buildTransportStream(tc.packetSize, tc.mdpm), 0600))
+
+			metadata, err := readMetadata(path)
+			got := metadata.captureTime
+
+			if tc.wantErr {
+				assert.ErrorIs(t, err, errNoMetadata)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// buildTransportStream returns a transport stream of three packets, with the MDPM tags in the second one.
+func buildTransportStream(packetSize int, mdpm []byte) []byte {
+	syncOffset := packetSize - tsPacketSize
+	b := make([]byte, 3*packetSize)
+	for i := 0; i < 3; i++ {
+		b[i*packetSize+syncOffset] = 0x47
+	}
+	payload := append(append([]byte{}, mdpmMarker...), mdpm...)
+	copy(b[packetSize+syncOffset+20:], payload)
+	return b
+}
@@ -0,0 +1,142 @@
+package upload
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/log"
+)
+
+const (
+	// UploadBothPairing uploads all the files of a pair. It's the default value of the pairing policies.
+	UploadBothPairing = "upload-both"
+	// PreferJPEGPairing uploads only the JPEG file of a RAW+JPEG pair.
+	PreferJPEGPairing = "prefer-jpeg"
+	// PreferRAWPairing uploads only the RAW file of a RAW+JPEG pair.
+	PreferRAWPairing = "prefer-raw"
+	// SkipLiveVideoPairing uploads only the photo of a Live Photo, skipping its MOV video.
+	SkipLiveVideoPairing = "skip-video"
+)
+
+// siblingKind is the role of a file in a RAW+JPEG pair or a Live Photo.
+type siblingKind int
+
+const (
+	noSibling siblingKind = iota
+	jpegSibling
+	rawSibling
+	heifSibling
+	liveVideoSibling
+)
+
+// rawSiblingExtensions are the extensions of the RAW files paired with JPEG files. TIFF files are not included,
+// because they are usually scans or edited photos.
+var rawSiblingExtensions = []string{
+	".arw", ".srf", ".sr2", ".crw", ".cr2", ".cr3", ".dng", ".nef", ".nrw", ".orf", ".raf", ".raw", ".rw2",
+}
+
+// siblingKindOf returns the role of the file in a pair, using its extension.
+func siblingKindOf(path string) siblingKind {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch {
+	case jpegContent.matchesExtension(path):
+		return jpegSibling
+	case slices.Contains(rawSiblingExtensions, ext):
+		return rawSibling
+	case heifContent.matchesExtension(path):
+		return heifSibling
+	case ext == ".mov":
+		return liveVideoSibling
+	}
+	return noSibling
+}
+
+// siblingKey returns the key shared by the files of a pair: the path without extension, lowercased.
+func siblingKey(relativePath string) string {
+	return strings.ToLower(strings.TrimSuffix(relativePath, filepath.Ext(relativePath)))
+}
+
+// pairsSiblings returns true if any pairing policy skips files.
+func (job *UploadFolderJob) pairsSiblings() bool {
+	return job.RawJpegPairing == PreferJPEGPairing || job.RawJpegPairing == PreferRAWPairing ||
+		job.LivePhotoPairing == SkipLiveVideoPairing
+}
+
+// addSibling records a file that can be paired with others. Only files passing the filters are recorded.
+func (job *UploadFolderJob) addSibling(relativePath string) {
+	if !job.pairsSiblings() || siblingKindOf(relativePath) == noSibling {
+		return
+	}
+	key := siblingKey(relativePath)
+	job.siblings[key] = append(job.siblings[key], relativePath)
+}
+
+// pairedWith returns the sibling that is uploaded instead of the file, if the pairing policies skip it.
+func (job *UploadFolderJob) pairedWith(relativePath string) (string, bool) {
+	siblings := job.siblings[siblingKey(relativePath)]
+
+	find := func(kinds ...siblingKind) (string, bool) {
+		for _, kind := range kinds {
+			for _, sibling := range siblings {
+				if sibling == relativePath || siblingKindOf(sibling) != kind {
+					continue
+				}
+				// The video of a Live Photo is paired with a photo that is uploaded.
+				if _, skipped := job.pairedWith(sibling); !skipped {
+					return sibling, true
+				}
+			}
+		}
+		return "", false
+	}
+
+	switch kind := siblingKindOf(relativePath); {
+	case kind == rawSibling && job.RawJpegPairing == PreferJPEGPairing:
+		return find(jpegSibling)
+	case kind == jpegSibling && job.RawJpegPairing == PreferRAWPairing:
+		return find(rawSibling)
+	case kind == liveVideoSibling && job.LivePhotoPairing == SkipLiveVideoPairing:
+		return find(heifSibling, jpegSibling, rawSibling)
+	}
+	return "", false
+}
+
+// pairSiblings removes the files skipped by the pairing policies, adding them to the PairedFiles of the file
+// uploaded instead. Files paired with an already uploaded file are removed too, see PairedWithUploaded.
+func (job *UploadFolderJob) pairSiblings(files []scannedFile, logger log.Logger) []scannedFile {
+	if !job.pairsSiblings() {
+		return files
+	}
+
+	paired := make(map[string][]string)
+	result := make([]scannedFile, 0, len(files))
+	for _, file := range files {
+		relativePath := file.data.Path
+		if sibling, found := job.pairedWith(relativePath); found {
+			siblingPath := filepath.Join(job.SourceFolder, sibling)
+			logger.Debugf("Skipping file '%s', it's paired with '%s'.", file.item.Path, siblingPath)
+			if job.uploadedSiblings[sibling] {
+				if !job.trackedAsPaired[file.item.Path] {
+					job.pairedWithUploaded[file.item.Path] = siblingPath
+				}
+			} else {
+				paired[sibling] = append(paired[sibling], file.item.Path)
+			}
+			continue
+		}
+		result = append(result, file)
+	}
+
+	for i := range result {
+		result[i].item.PairedFiles = paired[result[i].data.Path]
+	}
+	return result
+}
+
+// PairedWithUploaded returns the files skipped by the last ScanFolder because their sibling, preferred by the
+// pairing policies, is already uploaded. They are indexed by file, with the path of the sibling, so they can be
+// tracked as paired. Files already tracked as paired are not included.
+func (job *UploadFolderJob) PairedWithUploaded() map[string]string {
+	return job.pairedWithUploaded
+}
@@ -0,0 +1,201 @@
+package upload_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/datastore/filetracker"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/filter"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/mock"
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/upload"
+)
+
+func TestUploadFolderJob_Pairing(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"IMG_0001.CR3", "IMG_0001.JPG",
+		"IMG_0002.HEIC", "IMG_0002.MOV",
+		"IMG_0003.NEF", "IMG_0003.jpg", "IMG_0003.mov",
+		"IMG_0004.CR3", "IMG_0005.MOV",
+		"trip/IMG_0001.JPG",
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte("x"), 0600))
+	}
+
+	testCases := []struct {
+		name             string
+		rawJpegPairing   string
+		livePhotoPairing string
+		uploaded         []string
+		want             map[string][]string
+	}{
+		{
+			name: "Should upload all the files by default",
+			want: map[string][]string{
+				"IMG_0001.CR3": nil, "IMG_0001.JPG": nil, "IMG_0002.HEIC": nil, "IMG_0002.MOV": nil, "IMG_0003.NEF": nil,
+				"IMG_0003.jpg": nil, "IMG_0003.mov": nil, "IMG_0004.CR3": nil, "IMG_0005.MOV": nil, "trip/IMG_0001.JPG": nil,
+			},
+		},
+		{
+			name:           "Should prefer JPEG files",
+			rawJpegPairing: upload.PreferJPEGPairing,
+			want: map[string][]string{
+				"IMG_0001.JPG": {"IMG_0001.CR3"}, "IMG_0002.HEIC": nil, "IMG_0002.MOV": nil, "IMG_0003.jpg": {"IMG_0003.NEF"},
+				"IMG_0003.mov": nil, "IMG_0004.CR3": nil, "IMG_0005.MOV": nil, "trip/IMG_0001.JPG": nil,
+			},
+		},
+		{
+			name:             "Should prefer RAW files and skip live videos",
+			rawJpegPairing:   upload.PreferRAWPairing,
+			livePhotoPairing: upload.SkipLiveVideoPairing,
+			want: map[string][]string{
+				"IMG_0001.CR3": {"IMG_0001.JPG"}, "IMG_0002.HEIC": {"IMG_0002.MOV"}, "IMG_0003.NEF": {"IMG_0003.jpg", "IMG_0003.mov"},
+				"IMG_0004.CR3": nil, "IMG_0005.MOV": nil, "trip/IMG_0001.JPG": nil,
+			},
+		},
+		{
+			name:           "Should skip files paired with uploaded files",
+			rawJpegPairing: upload.PreferJPEGPairing,
+			uploaded:       []string{"IMG_0001.JPG", "IMG_0003.jpg"},
+			want: map[string][]string{
+				"IMG_0002.HEIC": nil, "IMG_0002.MOV": nil, "IMG_0003.mov": nil, "IMG_0004.CR3": nil, "IMG_0005.MOV": nil,
+				"trip/IMG_0001.JPG": nil,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uploaded := make(map[string]bool)
+			for _, name := range tc.uploaded {
+				uploaded[filepath.Join(dir, name)] = true
+			}
+			u := upload.UploadFolderJob{
+				FileTracker:      &mock.FileTracker{IsUploadedFn: func(path string) bool { return uploaded[path] }},
+				SourceFolder:     dir,
+				Filter:           filter.MustCompile([]string{"_ALL_FILES_"}, nil),
+				RawJpegPairing:   tc.rawJpegPairing,
+				LivePhotoPairing: tc.livePhotoPairing,
+			}
+
+			foundItems, err := u.ScanFolder(&mock.Logger{})
+			require.NoError(t, err)
+
+			got := make(map[string][]string)
+			for _, item := range foundItems {
+				var paired []string
+				for _, p := range item.PairedFiles {
+					paired = append(paired, filepath.ToSlash(upload.RelativePath(dir, p)))
+				}
+				got[filepath.ToSlash(upload.RelativePath(dir, item.Path))] = paired
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestUploadFolderJob_PairingWithUploadedFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"IMG_0001.CR3", "IMG_0001.JPG"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("x"), 0600))
+	}
+
+	// The JPEG file was uploaded before the policy was enabled.
+	jpeg := filepath.Join(dir, "IMG_0001.JPG")
+	u := upload.UploadFolderJob{
+		FileTracker:  &mock.FileTracker{IsUploadedFn: func(path string) bool { return path == jpeg }},
+		SourceFolder: dir,
+		Filter:       filter.MustCompile([]string{"_ALL_FILES_"}, nil),
+	}
+
+	foundItems, err := u.ScanFolder(&mock.Logger{})
+	require.NoError(t, err)
+	require.Len(t, foundItems, 1)
+	assert.Empty(t, u.PairedWithUploaded())
+
+	u.RawJpegPairing = upload.PreferJPEGPairing
+
+	foundItems, err = u.ScanFolder(&mock.Logger{})
+	require.NoError(t, err)
+	assert.Empty(t, foundItems)
+	assert.Equal(t, map[string]string{filepath.Join(dir, "IMG_0001.CR3"): jpeg}, u.PairedWithUploaded())
+}
+
+func TestUploadFolderJob_PairedFilesAfterChangingThePolicy(t *testing.T) {
+	dir := t.TempDir()
+	raw, jpeg := filepath.Join(dir, "IMG_0001.CR3"), filepath.Join(dir, "IMG_0001.JPG")
+	for _, path := range []string{raw, jpeg} {
+		require.NoError(t, os.WriteFile(path, []byte("x"), 0600))
+	}
+
+	// The last run uploaded the JPEG file and skipped the RAW file.
+	tracker := filetracker.New(memoryRepository{})
+	require.NoError(t, tracker.MarkAsUploaded(jpeg))
+	require.NoError(t, tracker.MarkAsPaired(raw, jpeg))
+
+	u := upload.UploadFolderJob{
+		FileTracker:    tracker,
+		SourceFolder:   dir,
+		Filter:         filter.MustCompile([]string{"_ALL_FILES_"}, nil),
+		RawJpegPairing: upload.PreferJPEGPairing,
+	}
+
+	t.Run("Should skip paired file while the policy prefers its sibling", func(t *testing.T) {
+		foundItems, err := u.ScanFolder(&mock.Logger{})
+		require.NoError(t, err)
+		assert.Empty(t, foundItems)
+		// It's already tracked as paired.
+		assert.Empty(t, u.PairedWithUploaded())
+	})
+
+	t.Run("Should upload paired file when the policy changes", func(t *testing.T) {
+		u.RawJpegPairing = upload.UploadBothPairing
+		defer func() { u.RawJpegPairing = upload.PreferJPEGPairing }()
+
+		foundItems, err := u.ScanFolder(&mock.Logger{})
+		require.NoError(t, err)
+		require.Len(t, foundItems, 1)
+		assert.Equal(t, raw, foundItems[0].Path)
+	})
+
+	t.Run("Should upload paired file when its sibling is deleted", func(t *testing.T) {
+		require.NoError(t, os.Remove(jpeg))
+
+		foundItems, err := u.ScanFolder(&mock.Logger{})
+		require.NoError(t, err)
+		require.Len(t, foundItems, 1)
+		assert.Equal(t, raw, foundItems[0].Path)
+	})
+}
+
+// memoryRepository is a file tracker repository kept in memory.
+type memoryRepository map[string]filetracker.TrackedFile
+
+func (r memoryRepository) Get(key string) (filetracker.TrackedFile, bool) {
+	item, found := r[key]
+	return item, found
+}
+
+func (r memoryRepository) Put(key string, item filetracker.TrackedFile) error {
+	r[key] = item
+	return nil
+}
+
+func (r memoryRepository) Delete(key string) error {
+	delete(r, key)
+	return nil
+}
+
+func (r memoryRepository) Close() error {
+	return nil
+}
+
+func (r memoryRepository) Destroy() error {
+	return nil
+}
@@ -0,0 +1,38 @@
+package upload
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// sizeUnits are the multipliers of the size units. Units are powers of 1024.
+var sizeUnits = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{suffix: "TB", multiplier: 1 << 40},
+	{suffix: "GB", multiplier: 1 << 30},
+	{suffix: "MB", multiplier: 1 << 20},
+	{suffix: "KB", multiplier: 1 << 10},
+	{suffix: "B", multiplier: 1},
+}
+
+// ParseFileSize returns the size in bytes of a value like "500", "300KB", "1.5MB" or "2 GB". Units are
+// case-insensitive, and they are powers of 1024.
+func ParseFileSize(value string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	multiplier := 1.0
+	for _, unit := range sizeUnits {
+		if number, found := strings.CutSuffix(s, unit.suffix); found {
+			s, multiplier = strings.TrimSpace(number), unit.multiplier
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid file size '%s'", value)
+	}
+	return int64(n * multiplier), nil
+}
@@ -0,0 +1,38 @@
+package upload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFileSize(t *testing.T) {
+	var testCases = []struct {
+		in          string
+		want        int64
+		errExpected bool
+	}{
+		{in: "500", want: 500},
+		{in: "500B", want: 500},
+		{in: "300KB", want: 300 * 1024},
+		{in: "1.5MB", want: 1536 * 1024},
+		{in: "2 gb", want: 2 << 30},
+		{in: "1TB", want: 1 << 40},
+		{in: "", errExpected: true},
+		{in: "MB", errExpected: true},
+		{in: "-1KB", errExpected: true},
+		{in: "10XB", errExpected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := ParseFileSize(tc.in)
+			if tc.errExpected {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
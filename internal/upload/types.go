@@ -1,14 +1,98 @@
 package upload
 
+import (
+	"time"
+
+	"github.com/gphotosuploader/gphotos-uploader-cli/internal/datastore/filetracker"
+)
+
 // UploadFolderJob represents a job to upload all photos from the specified folder
 type UploadFolderJob struct {
 	FileTracker FileTracker
 
 	SourceFolder string
 	Album        string
-	Filter       FileFilterer
+	// AlbumRules set the album of the files that match them, instead of the Album option. The first matching
+	// rule is used.
+	AlbumRules []AlbumRule
+	Albums     []string
+	// AlbumNameLocale is the language of the month and weekday names in album name templates.
+	// If it's empty, DefaultAlbumNameLocale is used.
+	AlbumNameLocale string
+	AlbumMap        map[string]string
+	// AlbumTitles sets how album names are sanitized.
+	AlbumTitles AlbumTitleOptions
+	Filter      FileFilterer
+	// DetectByContent allows the files using their content instead of their extension, so files with wrong or
+	// missing extensions are uploaded too. Files that are not supported photos or videos are skipped.
+	DetectByContent bool
+	// Conditions are the size, age and capture time conditions that files must meet to be uploaded.
+	Conditions FileConditions
+	// CaseInsensitivePatterns makes the patterns of the ignore files match case-insensitively.
+	CaseInsensitivePatterns bool
+
+	// RawJpegPairing sets which files of a RAW+JPEG pair are uploaded: UploadBothPairing (default),
+	// PreferJPEGPairing or PreferRAWPairing.
+	RawJpegPairing string
+	// LivePhotoPairing sets if the video of a Live Photo is uploaded: UploadBothPairing (default) or
+	// SkipLiveVideoPairing.
+	LivePhotoPairing string
+
+	// ItemsOrder sets the upload order of the files: NoItemsOrder (default), OldestFirstItemsOrder or
+	// NewestFirstItemsOrder.
+	ItemsOrder string
+
+	// DateSources are the sources of the capture time of the files, in order of preference.
+	// If it's empty, DefaultDateSources are used.
+	DateSources []string
+
+	// FilenameDateParser finds the dates in file names. If it's nil, only the built-in patterns are used.
+	FilenameDateParser *FilenameDateParser
+
+	// EventGap is the time between files to start a new event, see EventAlbumOption.
+	// If it's zero, DefaultEventGap is used.
+	EventGap time.Duration
+	// EventAlbumName is the template of the event album names. It can use the %_event_start%, %_event_end% and
+	// %_event_dates% tokens. If it's empty, DefaultEventAlbumName is used.
+	EventAlbumName string
+
+	// albumTemplates are the compiled album name templates, indexed by template.
+	albumTemplates map[string]*albumTemplate
+
+	// albumTitles sanitizes the album names. It's created on first use.
+	albumTitles *albumTitleSanitizer
+
+	// albumOverrides are the Album option values set by override files, indexed by folder relative to the
+	// SourceFolder. See AlbumOverrideFilename.
+	albumOverrides map[string]string
+
+	// ignoreRules are the rules of the ignore files, indexed by folder relative to the SourceFolder.
+	// See IgnoreFilename.
+	ignoreRules map[string][]ignoreRule
+
+	// events are the events of the files, used by the EventAlbumOption.
+	events *eventClusters
+
+	// siblings are the files that can be paired by the pairing policies, indexed by siblingKey.
+	siblings map[string][]string
+	// uploadedSiblings are the files of siblings that are already uploaded.
+	uploadedSiblings map[string]bool
+	// pairedWithUploaded are the files skipped by the pairing policies because their sibling is already uploaded,
+	// indexed by file, with the path of the sibling.
+	pairedWithUploaded map[string]string
+	// trackedAsPaired are the files tracked as skipped by the pairing policies, so they are not tracked again.
+	trackedAsPaired map[string]bool
 }
 
+const (
+	// OldestFirstItemsOrder uploads the files sorted by capture time, the oldest first.
+	OldestFirstItemsOrder = "oldest-first"
+	// NewestFirstItemsOrder uploads the files sorted by capture time, the newest first.
+	NewestFirstItemsOrder = "newest-first"
+	// NoItemsOrder uploads the files in the order they are found in the source folder.
+	NoItemsOrder = "none"
+)
+
 // FileTracker represents a service to track already uploaded files.
 type FileTracker interface {
 	MarkAsUploaded(file string) error
@@ -21,3 +105,21 @@ type FileFilterer interface {
 	IsAllowed(path string) bool
 	IsExcluded(path string) bool
 }
+
+// pendingAlbumsTracker is implemented by file trackers that keep the uploaded files that were not added to all their
+// extra albums yet, like filetracker.FileTracker.
+type pendingAlbumsTracker interface {
+	PendingMediaItem(file string) (string, bool)
+}
+
+// trackedFileReader is implemented by file trackers that return the stored state of the files, so they can be
+// checked without updating the tracker, like filetracker.FileTracker.
+type trackedFileReader interface {
+	TrackedFile(file string) (filetracker.TrackedFile, bool)
+	Hash(file string) (string, error)
+}
+
+// filterExplainer is implemented by filters that tell the pattern deciding if a path is allowed, like filter.Filter.
+type filterExplainer interface {
+	Explain(path string) (pattern string, include bool, found bool)
+}
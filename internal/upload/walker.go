@@ -3,7 +3,9 @@ package upload
 import (
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/facebookgo/symwalk"
 
@@ -13,12 +15,89 @@ import (
 // ScanFolder return the list of Items{} to be uploaded. It scans the folder and skip
 // non allowed files (includePatterns & excludePattens).
 func (job *UploadFolderJob) ScanFolder(logger log.Logger) ([]FileItem, error) {
-	var result []FileItem
-	err := symwalk.Walk(job.SourceFolder, job.getItemToUploadFn(&result, logger))
+	if err := job.compileAlbumTemplates(); err != nil {
+		return nil, err
+	}
+
+	job.albumOverrides = make(map[string]string)
+	job.ignoreRules = make(map[string][]ignoreRule)
+	job.events = newEventClusters()
+	job.siblings = make(map[string][]string)
+	job.uploadedSiblings = make(map[string]bool)
+	job.pairedWithUploaded = make(map[string]string)
+	job.trackedAsPaired = make(map[string]bool)
+
+	var files []scannedFile
+	err := symwalk.Walk(job.SourceFolder, job.getItemToUploadFn(&files, logger))
+
+	// Album names are calculated once all the files are found, so events include all of them.
+	job.events.cluster(job.eventGap())
+	files = job.pairSiblings(files, logger)
+	result := job.assignAlbums(files, logger)
+
+	job.sortItems(result)
 	return result, err
 }
 
-func (job *UploadFolderJob) getItemToUploadFn(reqs *[]FileItem, logger log.Logger) filepath.WalkFunc {
+// scannedFile is a file found in the source folder, with the data to calculate its album names.
+type scannedFile struct {
+	item FileItem
+	data templateData
+}
+
+// assignAlbums sets the albums of the files.
+func (job *UploadFolderJob) assignAlbums(files []scannedFile, logger log.Logger) []FileItem {
+	result := make([]FileItem, 0, len(files))
+	for _, file := range files {
+		item, data := file.item, file.data
+		if e, found := job.events.find(filepath.Dir(data.Path), data.CaptureTime); found {
+			data.EventStart, data.EventEnd = e.start, e.end
+		}
+
+		if names := job.albumNames(data, logger); len(names) > 0 {
+			item.AlbumName = names[0]
+			item.ExtraAlbumNames = names[1:]
+		}
+		item.AlbumID = job.mappedAlbumID(data.Path, item.AlbumName)
+
+		if item.AlbumID != "" {
+			logger.Debugf("Adding file '%s' to the upload list for album '%s' (id: %s).", item.Path, item.AlbumName, item.AlbumID)
+		} else {
+			logger.Debugf("Adding file '%s' to the upload list for album '%s'.", item.Path, item.AlbumName)
+		}
+		if len(item.ExtraAlbumNames) > 0 {
+			logger.Debugf("File '%s' will be added to extra albums: %s.", item.Path, strings.Join(item.ExtraAlbumNames, ", "))
+		}
+
+		result = append(result, item)
+	}
+	return result
+}
+
+// sortsItems returns true if the items are sorted by capture time.
+func (job *UploadFolderJob) sortsItems() bool {
+	return job.ItemsOrder == OldestFirstItemsOrder || job.ItemsOrder == NewestFirstItemsOrder
+}
+
+// sortItems sorts the items by capture time, following the ItemsOrder option.
+// Items with the same capture time keep the order they were found.
+func (job *UploadFolderJob) sortItems(items []FileItem) {
+	switch job.ItemsOrder {
+	case OldestFirstItemsOrder:
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].CaptureTime.Before(items[j].CaptureTime)
+		})
+	case NewestFirstItemsOrder:
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].CaptureTime.After(items[j].CaptureTime)
+		})
+	}
+}
+
+func (job *UploadFolderJob) getItemToUploadFn(reqs *[]scannedFile, logger log.Logger) filepath.WalkFunc {
+	// The age of the files is calculated from the start of the scan.
+	now := time.Now()
+
 	return func(fp string, fi os.FileInfo, errP error) error {
 		if fi == nil {
 			return nil
@@ -28,10 +107,32 @@ func (job *UploadFolderJob) getItemToUploadFn(reqs *[]FileItem, logger log.Logge
 
 		// If a directory is excluded, skip it!
 		if fi.IsDir() {
-			if job.Filter.IsExcluded(relativePath) {
+			if job.Filter.IsExcluded(relativePath) || job.isIgnored(relativePath, true) {
 				logger.Debugf("Skipping excluded directory '%s'.", fp)
 				return filepath.SkipDir
 			}
+
+			rules, err := job.readIgnoreFile(fp)
+			switch {
+			case err != nil:
+				logger.Warnf("Ignoring ignore file in '%s': %s", fp, err)
+			case len(rules) > 0:
+				job.ignoreRules[relativePath] = rules
+			}
+
+			option, found, err := job.readAlbumOverride(fp)
+			switch {
+			case err != nil:
+				logger.Warnf("Ignoring album override file in '%s': %s", fp, err)
+			case found:
+				logger.Debugf("Using album '%s' for files in '%s'.", option, fp)
+				job.albumOverrides[relativePath] = option
+			}
+			return nil
+		}
+
+		// Album override and ignore files are not uploaded.
+		if fi.Name() == AlbumOverrideFilename || fi.Name() == IgnoreFilename {
 			return nil
 		}
 
@@ -39,29 +140,111 @@ func (job *UploadFolderJob) getItemToUploadFn(reqs *[]FileItem, logger log.Logge
 		// configuration file. It uses relative Path from the source folder Path to facilitate
 		// then set up of includePatterns and excludePatterns.
 
-		if !job.Filter.IsAllowed(relativePath) {
+		if job.isIgnored(relativePath, false) {
+			logger.Debugf("Skipping excluded file '%s'.", fp)
+			return nil
+		}
+		allowed := job.Filter.IsAllowed(relativePath)
+		if !allowed && !job.DetectByContent {
 			logger.Debugf("Skipping excluded file '%s'.", fp)
 			return nil
 		}
+		uploaded, tracked := false, false
+		if job.DetectByContent {
+			// Already uploaded files were allowed by their content, so it's not read again.
+			uploaded, tracked = job.isUploaded(fp), true
+			if uploaded && !allowed && job.Filter.IsExcluded(relativePath) || !uploaded && !job.isAllowedByContent(fp, relativePath, logger) {
+				logger.Debugf("Skipping excluded file '%s'.", fp)
+				return nil
+			}
+		}
+
+		if !job.Conditions.matchesFile(fi.Size(), fi.ModTime(), now) {
+			logger.Debugf("Skipping file '%s' due to its size or age.", fp)
+			return nil
+		}
+
+		// Already uploaded files are paired too, so their siblings are skipped.
+		job.addSibling(relativePath)
+
+		// check completed uploads db for previous uploads.
+		// Already uploaded files are still needed to calculate the events of their folder.
+		if !tracked {
+			uploaded = job.isUploaded(fp)
+		}
+		if uploaded {
+			job.uploadedSiblings[relativePath] = true
+		}
 
-		// check completed uploads db for previous uploads
-		if job.FileTracker.IsUploaded(fp) {
+		// Uploaded files that were not added to all their extra albums are listed again, with their media item.
+		var mediaItemID string
+		if uploaded {
+			mediaItemID = job.pendingMediaItem(fp)
+			uploaded = mediaItemID == ""
+		}
+		if uploaded && !job.mayUseEvents() {
 			logger.Debugf("Skipping already uploaded file '%s'.", fp)
 			return nil
 		}
 
-		albumName := job.albumName(relativePath, fi.ModTime())
-		logger.Debugf("Adding file '%s' to the upload list for album '%s'.", fp, albumName)
+		metadata := job.readFileMetadata(fp, fi.ModTime())
+		if !job.Conditions.matchesCaptureTime(metadata.captureTime) {
+			logger.Debugf("Skipping file '%s' due to its capture date.", fp)
+			return nil
+		}
+
+		data := job.templateData(relativePath, fi.Size(), metadata)
+		if job.usesEvents(data) {
+			job.events.add(filepath.Dir(relativePath), metadata.captureTime)
+		}
+		if uploaded {
+			logger.Debugf("Skipping already uploaded file '%s'.", fp)
+			return nil
+		}
 
 		// set file upload Options depending on folder upload Options
-		*reqs = append(*reqs, FileItem{
-			Path:      fp,
-			AlbumName: albumName,
+		*reqs = append(*reqs, scannedFile{
+			item: FileItem{Path: fp, CaptureTime: metadata.captureTime, MediaItemID: mediaItemID},
+			data: data,
 		})
 		return nil
 	}
 }
 
+// isUploaded checks if the file was already uploaded. Files skipped by a pairing policy are not considered uploaded,
+// so they are skipped only while the current policies pair them with an existing sibling.
+func (job *UploadFolderJob) isUploaded(fp string) bool {
+	if !job.FileTracker.IsUploaded(fp) {
+		return false
+	}
+	if job.isTrackedAsPaired(fp) {
+		job.trackedAsPaired[fp] = true
+		return false
+	}
+	return true
+}
+
+// isTrackedAsPaired returns true if the file is tracked as skipped by a pairing policy.
+func (job *UploadFolderJob) isTrackedAsPaired(fp string) bool {
+	reader, ok := job.FileTracker.(trackedFileReader)
+	if !ok {
+		return false
+	}
+	stored, found := reader.TrackedFile(fp)
+	return found && stored.PairedWith != ""
+}
+
+// pendingMediaItem returns the media item of an uploaded file that was not added to all its extra albums yet, or an
+// empty string if there isn't any.
+func (job *UploadFolderJob) pendingMediaItem(fp string) string {
+	tracker, ok := job.FileTracker.(pendingAlbumsTracker)
+	if !ok {
+		return ""
+	}
+	mediaItemID, _ := tracker.PendingMediaItem(fp)
+	return mediaItemID
+}
+
 // RelativePath returns a path relative to the base.
 // If a relative path could not be calculated or it contains ' ../`,
 // returns the original path.
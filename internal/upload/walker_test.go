@@ -3,8 +3,11 @@ package upload_test
 import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gphotosuploader/gphotos-uploader-cli/internal/filter"
 	"github.com/gphotosuploader/gphotos-uploader-cli/internal/mock"
@@ -132,3 +135,311 @@ func getIncludedFilesByScanFolder(includePatterns []string, excludePatterns []st
 
 	return results, nil
 }
+
+func TestWalker_SortsByCaptureTime(t *testing.T) {
+	var testCases = []struct {
+		order string
+		want  []string
+	}{
+		{order: "", want: []string{"testdata/SampleJPGImage.jpg", "testdata/ScreenShotJPG.jpg"}},
+		{order: upload.OldestFirstItemsOrder, want: []string{"testdata/SampleJPGImage.jpg", "testdata/ScreenShotJPG.jpg"}},
+		{order: upload.NewestFirstItemsOrder, want: []string{"testdata/ScreenShotJPG.jpg", "testdata/SampleJPGImage.jpg"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.order, func(t *testing.T) {
+			u := upload.UploadFolderJob{
+				FileTracker:  &mock.FileTracker{IsUploadedFn: func(path string) bool { return false }},
+				SourceFolder: "testdata",
+				Filter:       filter.MustCompile([]string{"*.jpg"}, []string{"AlreadyUploaded*", "folder*"}),
+				ItemsOrder:   tc.order,
+			}
+
+			foundItems, err := u.ScanFolder(&mock.Logger{})
+			require.NoError(t, err)
+
+			var got []string
+			for _, i := range foundItems {
+				got = append(got, i.Path)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestWalker_AlbumOverrideFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"trip/" + upload.AlbumOverrideFilename:   "# Album for the trip\n\nSummer in Paris\n",
+		"trip/a.jpg":                             "",
+		"trip/day1/b.jpg":                        "",
+		"events/" + upload.AlbumOverrideFilename: "template:%_directory% party",
+		"events/birthday/c.jpg":                  "",
+		"broken/" + upload.AlbumOverrideFilename: "template:%_ABC%",
+		"broken/d.jpg":                           "",
+		"other/e.jpg":                            "",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+
+	u := upload.UploadFolderJob{
+		FileTracker:  &mock.FileTracker{IsUploadedFn: func(path string) bool { return false }},
+		SourceFolder: dir,
+		Album:        "auto:folderName",
+		Filter:       filter.MustCompile([]string{"_ALL_FILES_"}, nil),
+		ItemsOrder:   upload.NoItemsOrder,
+	}
+
+	foundItems, err := u.ScanFolder(&mock.Logger{})
+	require.NoError(t, err)
+
+	got := make(map[string]string)
+	for _, i := range foundItems {
+		got[upload.RelativePath(dir, i.Path)] = i.AlbumName
+	}
+	assert.Equal(t, map[string]string{
+		"trip/a.jpg":            "Summer in Paris",
+		"trip/day1/b.jpg":       "Summer in Paris",
+		"events/birthday/c.jpg": "birthday party",
+		"broken/d.jpg":          "broken",
+		"other/e.jpg":           "other",
+	}, got)
+}
+
+func TestWalker_EventAlbums(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]time.Time{
+		"Paris/a.jpg":        time.Date(2024, time.May, 1, 10, 0, 0, 0, time.Local),
+		"Paris/b.jpg":        time.Date(2024, time.May, 2, 9, 0, 0, 0, time.Local),
+		"Paris/uploaded.jpg": time.Date(2024, time.May, 3, 8, 0, 0, 0, time.Local),
+		"Paris/c.jpg":        time.Date(2024, time.May, 20, 18, 0, 0, 0, time.Local),
+		"Rome/d.jpg":         time.Date(2024, time.May, 2, 9, 0, 0, 0, time.Local),
+	}
+	for name, modTime := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte("not an image"), 0600))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	u := upload.UploadFolderJob{
+		FileTracker:  &mock.FileTracker{IsUploadedFn: func(path string) bool { return strings.Contains(path, "uploaded") }},
+		SourceFolder: dir,
+		Album:        upload.EventAlbumOption,
+		Filter:       filter.MustCompile([]string{"_ALL_FILES_"}, nil),
+		DateSources:  []string{upload.ModTimeDateSource},
+		EventGap:     24 * time.Hour,
+	}
+
+	foundItems, err := u.ScanFolder(&mock.Logger{})
+	require.NoError(t, err)
+
+	got := make(map[string]string)
+	for _, i := range foundItems {
+		got[upload.RelativePath(dir, i.Path)] = i.AlbumName
+	}
+	assert.Equal(t, map[string]string{
+		"Paris/a.jpg": "Paris 2024-05-01",
+		"Paris/b.jpg": "Paris 2024-05-01",
+		"Paris/c.jpg": "Paris 2024-05-20",
+		"Rome/d.jpg":  "Rome 2024-05-02",
+	}, got)
+}
+
+func TestWalker_DetectByContentWithAllowedExtensions(t *testing.T) {
+	testCases := []struct {
+		name   string
+		file   string
+		source string
+		want   []string
+	}{
+		{name: "Should report mismatched extension", file: "IMG_0001.jpg", source: "testdata/SamplePNGImage.png", want: []string{"IMG_0001.jpg"}},
+		{name: "Should skip unsupported content", file: "IMG_0002.jpg", source: "testdata/SampleText.txt", want: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			b, err := os.ReadFile(tc.source)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, tc.file), b, 0600))
+
+			u := upload.UploadFolderJob{
+				FileTracker:     &mock.FileTracker{IsUploadedFn: func(path string) bool { return false }},
+				SourceFolder:    dir,
+				Filter:          filter.MustCompile([]string{"_ALL_FILES_"}, nil),
+				DetectByContent: true,
+			}
+
+			logger := &mock.Logger{}
+			foundItems, err := u.ScanFolder(logger)
+			require.NoError(t, err)
+
+			var got []string
+			for _, i := range foundItems {
+				got = append(got, upload.RelativePath(dir, i.Path))
+			}
+			assert.Equal(t, tc.want, got)
+			assert.True(t, logger.WarnfInvoked)
+		})
+	}
+}
+
+func TestWalker_PendingExtraAlbums(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"pending.jpg", "uploaded.jpg", "new.jpg"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte{}, 0600))
+	}
+
+	u := upload.UploadFolderJob{
+		FileTracker: &mock.FileTracker{
+			IsUploadedFn: func(path string) bool { return !strings.HasSuffix(path, "new.jpg") },
+			PendingMediaItemFn: func(path string) (string, bool) {
+				if strings.HasSuffix(path, "pending.jpg") {
+					return "media-item-id", true
+				}
+				return "", false
+			},
+		},
+		SourceFolder: dir,
+		Album:        "name:Trip",
+		Albums:       []string{"name:Family"},
+		Filter:       filter.MustCompile([]string{"_ALL_FILES_"}, nil),
+	}
+
+	foundItems, err := u.ScanFolder(&mock.Logger{})
+	require.NoError(t, err)
+
+	got := make(map[string]string)
+	for _, i := range foundItems {
+		got[upload.RelativePath(dir, i.Path)] = i.MediaItemID
+	}
+	assert.Equal(t, map[string]string{"pending.jpg": "media-item-id", "new.jpg": ""}, got)
+}
+
+func TestWalker_IgnoreFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		upload.IgnoreFilename:               "*.tmp\n/Drafts/\n",
+		"a.jpg":                             "",
+		"a.tmp":                             "",
+		"Drafts/b.jpg":                      "",
+		"trip/" + upload.IgnoreFilename:     "!keep.tmp\nday1/*.png\n",
+		"trip/keep.tmp":                     "",
+		"trip/other.tmp":                    "",
+		"trip/day1/c.jpg":                   "",
+		"trip/day1/c.png":                   "",
+		"excluded/d.jpg":                    "",
+		"excluded/" + upload.IgnoreFilename: "!d.jpg\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+
+	u := upload.UploadFolderJob{
+		FileTracker:  &mock.FileTracker{IsUploadedFn: func(path string) bool { return false }},
+		SourceFolder: dir,
+		Filter:       filter.MustCompile([]string{"_ALL_FILES_"}, []string{"excluded"}),
+		ItemsOrder:   upload.NoItemsOrder,
+	}
+
+	foundItems, err := u.ScanFolder(&mock.Logger{})
+	require.NoError(t, err)
+
+	var got []string
+	for _, i := range foundItems {
+		got = append(got, filepath.ToSlash(upload.RelativePath(dir, i.Path)))
+	}
+	assert.ElementsMatch(t, []string{"a.jpg", "trip/keep.tmp", "trip/day1/c.jpg"}, got)
+}
+
+func TestWalker_FileConditions(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	files := map[string]struct {
+		size    int
+		modTime time.Time
+	}{
+		"recent.jpg":    {size: 100, modTime: now.Add(-24 * time.Hour)},
+		"thumbnail.jpg": {size: 10, modTime: now.Add(-24 * time.Hour)},
+		"huge.jpg":      {size: 1000, modTime: now.Add(-24 * time.Hour)},
+		"old.jpg":       {size: 100, modTime: now.Add(-60 * 24 * time.Hour)},
+	}
+	for name, file := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, make([]byte, file.size), 0600))
+		require.NoError(t, os.Chtimes(path, file.modTime, file.modTime))
+	}
+
+	u := upload.UploadFolderJob{
+		FileTracker:  &mock.FileTracker{IsUploadedFn: func(path string) bool { return false }},
+		SourceFolder: dir,
+		Filter:       filter.MustCompile([]string{"_ALL_FILES_"}, nil),
+		DateSources:  []string{upload.ModTimeDateSource},
+		Conditions: upload.FileConditions{
+			MinSize:   50,
+			MaxSize:   500,
+			NewerThan: 30 * 24 * time.Hour,
+		},
+	}
+
+	foundItems, err := u.ScanFolder(&mock.Logger{})
+	require.NoError(t, err)
+
+	var got []string
+	for _, i := range foundItems {
+		got = append(got, upload.RelativePath(dir, i.Path))
+	}
+	assert.Equal(t, []string{"recent.jpg"}, got)
+
+	t.Run("CaptureDate", func(t *testing.T) {
+		u.Conditions = upload.FileConditions{CapturedBefore: now.Add(-30 * 24 * time.Hour)}
+
+		foundItems, err := u.ScanFolder(&mock.Logger{})
+		require.NoError(t, err)
+
+		require.Len(t, foundItems, 1)
+		assert.Equal(t, "old.jpg", upload.RelativePath(dir, foundItems[0].Path))
+	})
+}
+
+func TestWalker_DetectByContent(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"IMG_0001.jpg":           "testdata/SampleJPGImage.jpg",
+		"IMG_0002.dat":           "testdata/SampleJPGImage.jpg",
+		"IMG_0003.jpeg_original": "testdata/SampleJPGImage.jpg",
+		"IMG_0004.jpg":           "testdata/SamplePNGImage.png",
+		"not-an-image.jpg":       "testdata/SampleText.txt",
+		"VID_0001.dat":           "testdata/SampleVideo.mp4",
+		"excluded/IMG_0005.dat":  "testdata/SampleJPGImage.jpg",
+	}
+	for name, source := range files {
+		b, err := os.ReadFile(source)
+		require.NoError(t, err)
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, b, 0600))
+	}
+
+	u := upload.UploadFolderJob{
+		FileTracker:     &mock.FileTracker{IsUploadedFn: func(path string) bool { return false }},
+		SourceFolder:    dir,
+		Filter:          filter.MustCompile([]string{"_IMAGE_EXTENSIONS_"}, []string{"excluded/**"}),
+		DetectByContent: true,
+	}
+
+	foundItems, err := u.ScanFolder(&mock.Logger{})
+	require.NoError(t, err)
+
+	var got []string
+	for _, i := range foundItems {
+		got = append(got, filepath.ToSlash(upload.RelativePath(dir, i.Path)))
+	}
+	assert.ElementsMatch(t, []string{"IMG_0001.jpg", "IMG_0002.dat", "IMG_0003.jpeg_original", "IMG_0004.jpg"}, got)
+}